@@ -10,6 +10,10 @@ const (
 	actionEvents mangoAction = iota
 	actionAllUsers
 
+	actionFetchClient
+	actionEditClient
+	actionUploadClientLogo
+
 	actionCreateNaturalUser
 	actionEditNaturalUser
 	actionFetchNaturalUser
@@ -21,6 +25,10 @@ const (
 	actionFetchUser
 	actionFetchUserTransfers
 	actionFetchUserWallets
+	actionFetchUserRegulatory
+	actionFetchUserEMoney
+	actionFetchUserEMoneyYear
+	actionFetchUserEMoneyMonth
 	actionFetchUserCards
 	actionFetchUserBankAccounts
 
@@ -29,19 +37,55 @@ const (
 	actionFetchWallet
 	actionFetchWalletTransactions
 
+	actionFetchClientWallets
+	actionFetchClientWalletsByFundsType
+	actionFetchClientWallet
+	actionFetchClientWalletTransactions
+
 	actionCreateTransfer
 	actionFetchTransfer
 
+	actionFetchRepudiation
+	actionFetchRepudiationRefunds
+	actionCreateSettlementTransfer
+	actionFetchSettlementTransfer
+
 	actionFetchPayIn
 	actionCreateWebPayIn
 	actionCreateDirectPayIn
 	actionCreateBankwireDirectPayIn
 	actionCreateDirectDebitWebPayIn
+	actionCreateDirectDebitDirectPayIn
+	actionCreatePreauthorizedPayIn
+	actionCreateRecurringPayIn
+	actionCreatePayPalWebPayIn
+	actionCreateApplePayPayIn
+	actionCreateGooglePayPayIn
+	actionCreateIdealWebPayIn
+	actionCreateBancontactWebPayIn
+	actionCreatePayconiqWebPayIn
+
+	actionCreateRecurringPayInRegistration
+	actionEditRecurringPayInRegistration
+	actionFetchRecurringPayInRegistration
+	actionFetchRecurringPayInRegistrationPayIns
 
 	actionCreateCardRegistration
 	actionSendCardRegistrationData
+	actionFetchCardRegistration
 
 	actionFetchCard
+	actionEditCard
+	actionFetchCardsByFingerprint
+	actionFetchCardTransactions
+	actionCreateCardValidation
+	actionFetchCardValidation
+
+	actionCreateCardPreAuthorization
+	actionEditCardPreAuthorization
+	actionFetchCardPreAuthorization
+	actionFetchUserCardPreAuthorizations
+	actionFetchCardCardPreAuthorizations
 
 	actionCreateTransferRefund
 	actionCreatePayInRefund
@@ -49,11 +93,21 @@ const (
 
 	actionCreateBankAccount
 	actionFetchBankAccount
+	actionEditBankAccount
+	actionFetchBankAccountTransactions
 
 	actionCreateBankingAlias
+	actionCreateGBBankingAlias
+	actionEditBankingAlias
 	actionFetchBankingAlias
 	actionFetchBankingAliases
 
+	actionCreateMandate
+	actionFetchMandate
+	actionCancelMandate
+	actionFetchUserMandates
+	actionFetchBankAccountMandates
+
 	actionCreatePayOut
 	actionFetchPayOut
 
@@ -64,6 +118,13 @@ const (
 	actionFetchUserKYCDocuments
 	actionFetchAllKYCDocuments
 
+	actionCreateUboDeclaration
+	actionFetchUboDeclaration
+	actionFetchUserUboDeclarations
+	actionSubmitUboDeclaration
+	actionCreateUbo
+	actionEditUbo
+
 	actionCreateHook
 	actionUpdateHook
 	actionFetchHook
@@ -85,6 +146,21 @@ var mangoRequests = map[mangoAction]mangoRequest{
 		"/events",
 		nil,
 	},
+	actionFetchClient: {
+		"GET",
+		"/clients",
+		nil,
+	},
+	actionEditClient: {
+		"PUT",
+		"/clients",
+		nil,
+	},
+	actionUploadClientLogo: {
+		"PUT",
+		"/clients/logo",
+		nil,
+	},
 	actionCreateNaturalUser: {
 		"POST",
 		"/users/natural",
@@ -135,6 +211,26 @@ var mangoRequests = map[mangoAction]mangoRequest{
 		"/users/{{Id}}/wallets",
 		JsonObject{"Id": ""},
 	},
+	actionFetchUserRegulatory: {
+		"GET",
+		"/users/{{Id}}/Regulatory",
+		JsonObject{"Id": ""},
+	},
+	actionFetchUserEMoney: {
+		"GET",
+		"/users/{{Id}}/emoney",
+		JsonObject{"Id": ""},
+	},
+	actionFetchUserEMoneyYear: {
+		"GET",
+		"/users/{{Id}}/emoney/{{Year}}",
+		JsonObject{"Id": "", "Year": ""},
+	},
+	actionFetchUserEMoneyMonth: {
+		"GET",
+		"/users/{{Id}}/emoney/{{Year}}/{{Month}}",
+		JsonObject{"Id": "", "Year": "", "Month": ""},
+	},
 	actionFetchUserCards: {
 		"GET",
 		"/users/{{Id}}/cards",
@@ -160,6 +256,26 @@ var mangoRequests = map[mangoAction]mangoRequest{
 		"/wallets/{{Id}}/transactions",
 		JsonObject{"Id": ""},
 	},
+	actionFetchClientWallets: {
+		"GET",
+		"/clients/wallets",
+		nil,
+	},
+	actionFetchClientWalletsByFundsType: {
+		"GET",
+		"/clients/wallets/{{FundsType}}",
+		JsonObject{"FundsType": ""},
+	},
+	actionFetchClientWallet: {
+		"GET",
+		"/clients/wallets/{{FundsType}}/{{Currency}}",
+		JsonObject{"FundsType": "", "Currency": ""},
+	},
+	actionFetchClientWalletTransactions: {
+		"GET",
+		"/clients/wallets/{{FundsType}}/{{Currency}}/transactions",
+		JsonObject{"FundsType": "", "Currency": ""},
+	},
 	actionCreateTransfer: {
 		"POST",
 		"/transfers",
@@ -170,6 +286,26 @@ var mangoRequests = map[mangoAction]mangoRequest{
 		"/transfers/{{Id}}",
 		JsonObject{"Id": ""},
 	},
+	actionFetchRepudiation: {
+		"GET",
+		"/repudiations/{{Id}}",
+		JsonObject{"Id": ""},
+	},
+	actionFetchRepudiationRefunds: {
+		"GET",
+		"/repudiations/{{Id}}/refunds",
+		JsonObject{"Id": ""},
+	},
+	actionCreateSettlementTransfer: {
+		"POST",
+		"/repudiations/{{RepudiationId}}/settlementtransfer",
+		JsonObject{"RepudiationId": ""},
+	},
+	actionFetchSettlementTransfer: {
+		"GET",
+		"/settlements/{{Id}}",
+		JsonObject{"Id": ""},
+	},
 	actionFetchPayIn: {
 		"GET",
 		"/payins/{{Id}}",
@@ -195,6 +331,71 @@ var mangoRequests = map[mangoAction]mangoRequest{
 		"/payins/directdebit/web",
 		nil,
 	},
+	actionCreateDirectDebitDirectPayIn: {
+		"POST",
+		"/payins/directdebit/direct",
+		nil,
+	},
+	actionCreatePreauthorizedPayIn: {
+		"POST",
+		"/payins/preauthorized/direct",
+		nil,
+	},
+	actionCreateRecurringPayIn: {
+		"POST",
+		"/payins/recurring/card/direct",
+		nil,
+	},
+	actionCreatePayPalWebPayIn: {
+		"POST",
+		"/payins/paypal/web",
+		nil,
+	},
+	actionCreateApplePayPayIn: {
+		"POST",
+		"/payins/applepay/direct",
+		nil,
+	},
+	actionCreateGooglePayPayIn: {
+		"POST",
+		"/payins/payment-methods/googlepay",
+		nil,
+	},
+	actionCreateIdealWebPayIn: {
+		"POST",
+		"/payins/payment-methods/ideal",
+		nil,
+	},
+	actionCreateBancontactWebPayIn: {
+		"POST",
+		"/payins/payment-methods/bancontact",
+		nil,
+	},
+	actionCreatePayconiqWebPayIn: {
+		"POST",
+		"/payins/payment-methods/payconiq",
+		nil,
+	},
+	actionCreateRecurringPayInRegistration: {
+		"POST",
+		"/recurringpayinregistrations",
+		nil,
+	},
+	actionEditRecurringPayInRegistration: {
+		"PUT",
+		"/recurringpayinregistrations/{{Id}}",
+		JsonObject{"Id": ""},
+	},
+	actionFetchRecurringPayInRegistration: {
+		"GET",
+		"/recurringpayinregistrations/{{Id}}",
+		JsonObject{"Id": ""},
+	},
+	actionFetchRecurringPayInRegistrationPayIns: {
+		"GET",
+		"/recurringpayinregistrations/{{Id}}/payins",
+		JsonObject{"Id": ""},
+	},
 	actionCreateCardRegistration: {
 		"POST",
 		"/cardregistrations",
@@ -205,11 +406,66 @@ var mangoRequests = map[mangoAction]mangoRequest{
 		"/CardRegistrations/{{Id}}",
 		JsonObject{"Id": ""},
 	},
+	actionFetchCardRegistration: {
+		"GET",
+		"/cardregistrations/{{Id}}",
+		JsonObject{"Id": ""},
+	},
 	actionFetchCard: {
 		"GET",
 		"/cards/{{Id}}",
 		JsonObject{"Id": ""},
 	},
+	actionEditCard: {
+		"PUT",
+		"/cards/{{Id}}",
+		JsonObject{"Id": ""},
+	},
+	actionFetchCardsByFingerprint: {
+		"GET",
+		"/cards/fingerprints/{{Fingerprint}}",
+		JsonObject{"Fingerprint": ""},
+	},
+	actionFetchCardTransactions: {
+		"GET",
+		"/cards/{{Id}}/transactions",
+		JsonObject{"Id": ""},
+	},
+	actionCreateCardValidation: {
+		"POST",
+		"/cards/{{CardId}}/validation",
+		JsonObject{"CardId": ""},
+	},
+	actionFetchCardValidation: {
+		"GET",
+		"/cards/{{CardId}}/validation/{{Id}}",
+		JsonObject{"CardId": "", "Id": ""},
+	},
+	actionCreateCardPreAuthorization: {
+		"POST",
+		"/preauthorizations/card/direct",
+		nil,
+	},
+	actionEditCardPreAuthorization: {
+		"PUT",
+		"/preauthorizations/{{Id}}",
+		JsonObject{"Id": ""},
+	},
+	actionFetchCardPreAuthorization: {
+		"GET",
+		"/preauthorizations/{{Id}}",
+		JsonObject{"Id": ""},
+	},
+	actionFetchUserCardPreAuthorizations: {
+		"GET",
+		"/users/{{Id}}/preauthorizations",
+		JsonObject{"Id": ""},
+	},
+	actionFetchCardCardPreAuthorizations: {
+		"GET",
+		"/cards/{{Id}}/preauthorizations",
+		JsonObject{"Id": ""},
+	},
 	actionCreateTransferRefund: {
 		"POST",
 		"/transfers/{{TransferId}}/refunds",
@@ -235,6 +491,16 @@ var mangoRequests = map[mangoAction]mangoRequest{
 		"/users/{{UserId}}/bankaccounts/{{Id}}",
 		JsonObject{"UserId": "", "Id": ""},
 	},
+	actionEditBankAccount: {
+		"PUT",
+		"/users/{{UserId}}/bankaccounts/{{Id}}",
+		JsonObject{"UserId": "", "Id": ""},
+	},
+	actionFetchBankAccountTransactions: {
+		"GET",
+		"/bankaccounts/{{Id}}/transactions",
+		JsonObject{"Id": ""},
+	},
 	actionFetchUserBankAccounts: {
 		"GET",
 		"/users/{{Id}}/bankaccounts",
@@ -245,6 +511,16 @@ var mangoRequests = map[mangoAction]mangoRequest{
 		"/wallets/{{WalletId}}/bankingaliases/iban/",
 		JsonObject{"WalletId": ""},
 	},
+	actionCreateGBBankingAlias: {
+		"POST",
+		"/wallets/{{WalletId}}/bankingaliases/gb/",
+		JsonObject{"WalletId": ""},
+	},
+	actionEditBankingAlias: {
+		"PUT",
+		"/bankingaliases/{{BankingAliasId}}/",
+		JsonObject{"BankingAliasId": ""},
+	},
 	actionFetchBankingAlias: {
 		"GET",
 		"/bankingaliases/{{BankingAliasId}}/",
@@ -255,6 +531,31 @@ var mangoRequests = map[mangoAction]mangoRequest{
 		"/wallets/{{WalletId}}/bankingaliases/",
 		JsonObject{"WalletId": ""},
 	},
+	actionCreateMandate: {
+		"POST",
+		"/mandates/directdebit/web",
+		nil,
+	},
+	actionFetchMandate: {
+		"GET",
+		"/mandates/{{Id}}",
+		JsonObject{"Id": ""},
+	},
+	actionCancelMandate: {
+		"PUT",
+		"/mandates/{{Id}}/cancel",
+		JsonObject{"Id": ""},
+	},
+	actionFetchUserMandates: {
+		"GET",
+		"/users/{{UserId}}/mandates",
+		JsonObject{"UserId": ""},
+	},
+	actionFetchBankAccountMandates: {
+		"GET",
+		"/users/{{UserId}}/bankaccounts/{{Id}}/mandates",
+		JsonObject{"UserId": "", "Id": ""},
+	},
 	actionCreatePayOut: {
 		"POST",
 		"/payouts/bankwire",
@@ -295,6 +596,36 @@ var mangoRequests = map[mangoAction]mangoRequest{
 		"/kyc/documents",
 		nil,
 	},
+	actionCreateUboDeclaration: {
+		"POST",
+		"/users/{{UserId}}/kyc/ubodeclarations",
+		JsonObject{"UserId": ""},
+	},
+	actionFetchUboDeclaration: {
+		"GET",
+		"/users/{{UserId}}/kyc/ubodeclarations/{{Id}}",
+		JsonObject{"UserId": "", "Id": ""},
+	},
+	actionFetchUserUboDeclarations: {
+		"GET",
+		"/users/{{UserId}}/kyc/ubodeclarations",
+		JsonObject{"UserId": ""},
+	},
+	actionSubmitUboDeclaration: {
+		"PUT",
+		"/users/{{UserId}}/kyc/ubodeclarations/{{Id}}",
+		JsonObject{"UserId": "", "Id": ""},
+	},
+	actionCreateUbo: {
+		"POST",
+		"/users/{{UserId}}/kyc/ubodeclarations/{{DeclarationId}}/ubos",
+		JsonObject{"UserId": "", "DeclarationId": ""},
+	},
+	actionEditUbo: {
+		"PUT",
+		"/users/{{UserId}}/kyc/ubodeclarations/{{DeclarationId}}/ubos/{{Id}}",
+		JsonObject{"UserId": "", "DeclarationId": "", "Id": ""},
+	},
 
 	actionCreateHook: {
 		"POST",
@@ -0,0 +1,137 @@
+// Copyright 2014 Mathias Monnerville. All rights reserved.
+// Use of this source code is governed by a GPL
+// license that can be found in the LICENSE file.
+
+package mango
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Address is a postal address as expected by MangoPay.
+//
+// For compatibility with the previous API version, an address can also be
+// decoded from a plain JSON string, which is then stored in AddressLine1.
+type Address struct {
+	AddressLine1 string
+	AddressLine2 string
+	City         string
+	Region       string
+	PostalCode   string
+	Country      string // ISO 3166-1 alpha-2 code
+}
+
+func (a *Address) String() string {
+	parts := []string{}
+	for _, p := range []string{a.AddressLine1, a.AddressLine2,
+		strings.TrimSpace(a.PostalCode + " " + a.City), a.Region, a.Country} {
+		if p != "" {
+			parts = append(parts, p)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// IsEmpty returns true if a is nil or has no field set, as in addresses
+// returned by MangoPay for users created without one.
+func (a *Address) IsEmpty() bool {
+	return a == nil || *a == Address{}
+}
+
+// UnmarshalJSON decodes an address from either a JSON object or a legacy
+// JSON string.
+func (a *Address) UnmarshalJSON(b []byte) error {
+	var line string
+	if err := json.Unmarshal(b, &line); err == nil {
+		*a = Address{AddressLine1: line}
+		return nil
+	}
+	// Use a distinct type to avoid recursing into UnmarshalJSON.
+	type address Address
+	var v address
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	*a = Address(v)
+	return nil
+}
+
+// Validate checks that the address is complete: AddressLine1, City and a
+// valid ISO 3166-1 alpha-2 Country are required, as well as the PostalCode
+// for countries using postal codes.
+func (a *Address) Validate() error {
+	missing := []string{}
+	if strings.TrimSpace(a.AddressLine1) == "" {
+		missing = append(missing, "AddressLine1")
+	}
+	if strings.TrimSpace(a.City) == "" {
+		missing = append(missing, "City")
+	}
+	if a.Country == "" {
+		missing = append(missing, "Country")
+	} else if !isCountryCode(a.Country) {
+		return fmt.Errorf("invalid address: unknown country code %q", a.Country)
+	}
+	if strings.TrimSpace(a.PostalCode) == "" && a.Country != "" && !countriesWithoutPostalCode[a.Country] {
+		missing = append(missing, "PostalCode")
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("invalid address: missing %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// isCountryCode returns true if code is an ISO 3166-1 alpha-2 country code.
+func isCountryCode(code string) bool {
+	if len(code) != 2 {
+		return false
+	}
+	return strings.Contains(countryCodes, " "+code+" ")
+}
+
+// ISO 3166-1 alpha-2 country codes, space separated.
+const countryCodes = " AD AE AF AG AI AL AM AO AQ AR AS AT AU AW AX AZ" +
+	" BA BB BD BE BF BG BH BI BJ BL BM BN BO BQ BR BS BT BV BW BY BZ" +
+	" CA CC CD CF CG CH CI CK CL CM CN CO CR CU CV CW CX CY CZ" +
+	" DE DJ DK DM DO DZ EC EE EG EH ER ES ET FI FJ FK FM FO FR" +
+	" GA GB GD GE GF GG GH GI GL GM GN GP GQ GR GS GT GU GW GY" +
+	" HK HM HN HR HT HU ID IE IL IM IN IO IQ IR IS IT JE JM JO JP" +
+	" KE KG KH KI KM KN KP KR KW KY KZ LA LB LC LI LK LR LS LT LU LV LY" +
+	" MA MC MD ME MF MG MH MK ML MM MN MO MP MQ MR MS MT MU MV MW MX MY MZ" +
+	" NA NC NE NF NG NI NL NO NP NR NU NZ OM PA PE PF PG PH PK PL PM PN PR PS PT PW PY" +
+	" QA RE RO RS RU RW SA SB SC SD SE SG SH SI SJ SK SL SM SN SO SR SS ST SV SX SY SZ" +
+	" TC TD TF TG TH TJ TK TL TM TN TO TR TT TV TW TZ UA UG UM US UY UZ" +
+	" VA VC VE VG VI VN VU WF WS YE YT ZA ZM ZW "
+
+// Countries not using postal codes.
+var countriesWithoutPostalCode = map[string]bool{
+	"AE": true, "AG": true, "AO": true, "AW": true, "BF": true,
+	"BI": true, "BJ": true, "BO": true, "BS": true, "BW": true, "BZ": true,
+	"CD": true, "CF": true, "CG": true, "CI": true, "CK": true, "CM": true,
+	"DJ": true, "DM": true, "ER": true, "FJ": true, "GA": true, "GD": true,
+	"GH": true, "GM": true, "GQ": true, "GY": true, "HK": true, "IE": true,
+	"JM": true, "KE": true, "KI": true, "KM": true, "KN": true, "KP": true,
+	"LC": true, "ML": true, "MO": true, "MR": true, "MS": true, "MW": true,
+	"NR": true, "NU": true, "PA": true, "QA": true, "RW": true, "SB": true,
+	"SC": true, "SL": true, "SO": true, "SR": true, "ST": true, "SY": true,
+	"TD": true, "TF": true, "TG": true, "TK": true, "TL": true, "TO": true,
+	"TT": true, "TV": true, "UG": true, "VU": true, "YE": true, "ZW": true,
+}
+
+// Billing holds the billing details of a card payment, used for the 3DS2
+// risk assessment.
+type Billing struct {
+	FirstName string
+	LastName  string
+	Address   Address
+}
+
+// Shipping holds the shipping details of a card payment, used for the 3DS2
+// risk assessment.
+type Shipping struct {
+	FirstName string
+	LastName  string
+	Address   Address
+}
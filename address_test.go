@@ -0,0 +1,45 @@
+package mango
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestAddress_UnmarshalJSON(test *testing.T) {
+	var u NaturalUser
+	if err := json.Unmarshal([]byte(`{"Address":"1 rue de la Paix"}`), &u); err != nil {
+		test.Fatal("Unable to decode legacy address:", err)
+	}
+	if u.Address == nil || u.Address.AddressLine1 != "1 rue de la Paix" {
+		test.Fatalf("Invalid legacy address: %v", u.Address)
+	}
+	if err := json.Unmarshal([]byte(`{"Address":{"City":"Paris","Country":"FR"}}`), &u); err != nil {
+		test.Fatal("Unable to decode address:", err)
+	}
+	if u.Address.City != "Paris" || u.Address.Country != "FR" || u.Address.AddressLine1 != "" {
+		test.Fatalf("Invalid address: %v", u.Address)
+	}
+}
+
+func TestAddress_Validate(test *testing.T) {
+	cases := []struct {
+		addr   Address
+		errMsg string
+	}{
+		{*testAddress, ""},
+		{Address{AddressLine1: "Main Street", City: "Dublin", Country: "IE"}, ""},
+		{Address{AddressLine1: "Main Street", City: "Paris", Country: "FR"}, "missing PostalCode"},
+		{Address{PostalCode: "75002", Country: "FR"}, "missing AddressLine1, City"},
+		{Address{AddressLine1: "Main Street", City: "Paris", PostalCode: "75002", Country: "XX"}, "unknown country code"},
+	}
+	for _, c := range cases {
+		err := c.addr.Validate()
+		if c.errMsg == "" && err != nil {
+			test.Errorf("%v: unexpected error %v", c.addr, err)
+		}
+		if c.errMsg != "" && (err == nil || !strings.Contains(err.Error(), c.errMsg)) {
+			test.Errorf("%v: expected error containing %q, got %v", c.addr, c.errMsg, err)
+		}
+	}
+}
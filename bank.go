@@ -7,6 +7,7 @@ package mango
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 )
 
 // Bank account type.
@@ -28,6 +29,12 @@ var accountTypes = map[AccountType]string{
 	OTHER: "OTHER",
 }
 
+// Types of US bank accounts.
+const (
+	DepositAccountTypeChecking = "CHECKING"
+	DepositAccountTypeSavings  = "SAVINGS"
+)
+
 // List of bank accounts.
 type BankAccountList []*BankAccount
 
@@ -36,15 +43,17 @@ type BankAccountList []*BankAccount
 // types: IBAN, GB, US, CA or OTHER.
 //
 // This way, only one structure is used to unmarshal any JSON response related
-// to bank accounts.
+// to bank accounts. Use Details() to get the fields specific to the
+// account's type.
 //
 // See http://docs.mangopay.com/api-references/bank-accounts/
 type BankAccount struct {
 	ProcessIdent
 	Type         string // IBAN, GB, US, CA or OTHER
 	OwnerName    string
-	OwnerAddress string
+	OwnerAddress *Address
 	UserId       string
+	Active       bool
 	// Required for IBAN type
 	IBAN          string
 	BIC           string // For IBAN, OTHER
@@ -52,7 +61,8 @@ type BankAccount struct {
 	// Required for GB type
 	SortCode string
 	// Required for US type
-	ABA string
+	ABA                string
+	DepositAccountType string // CHECKING or SAVINGS, optional
 	// Required for CA type
 	BankName          string
 	InstitutionNumber string
@@ -61,22 +71,167 @@ type BankAccount struct {
 	Country string
 
 	service *MangoPay
-	atype   AccountType
 }
 
+// BankAccountDetails holds the fields specific to a type of bank account.
+// It is implemented by IBANBankAccount, GBBankAccount, USBankAccount,
+// CABankAccount and OtherBankAccount.
+type BankAccountDetails interface {
+	// Type returns the account type, i.e "IBAN".
+	Type() string
+	validate() error
+}
+
+// IBANBankAccount holds the details of an IBAN bank account.
 type IBANBankAccount struct {
+	IBAN string
+	BIC  string `json:",omitempty"`
+}
+
+func (d *IBANBankAccount) Type() string { return accountTypes[IBAN] }
+
+func (d *IBANBankAccount) validate() error {
+	if d.IBAN == "" {
+		return errors.New("missing full IBAN information")
+	}
+	if d.BIC != "" {
+		if err := ValidateBIC(d.BIC); err != nil {
+			return err
+		}
+	}
+	return ValidateIBAN(d.IBAN)
+}
+
+// GBBankAccount holds the details of a UK bank account.
+type GBBankAccount struct {
+	AccountNumber string
+	SortCode      string
+}
+
+func (d *GBBankAccount) Type() string { return accountTypes[GB] }
+
+func (d *GBBankAccount) validate() error {
+	if d.AccountNumber == "" || d.SortCode == "" {
+		return errors.New("missing full GB information")
+	}
+	return firstError(ValidateGBAccountNumber(d.AccountNumber), ValidateSortCode(d.SortCode))
+}
+
+// USBankAccount holds the details of a US bank account.
+type USBankAccount struct {
+	AccountNumber      string
+	ABA                string
+	DepositAccountType string `json:",omitempty"`
+}
+
+func (d *USBankAccount) Type() string { return accountTypes[US] }
+
+func (d *USBankAccount) validate() error {
+	if d.AccountNumber == "" || d.ABA == "" {
+		return errors.New("missing full US information")
+	}
+	switch d.DepositAccountType {
+	case "", DepositAccountTypeChecking, DepositAccountTypeSavings:
+	default:
+		return fmt.Errorf("invalid deposit account type %q", d.DepositAccountType)
+	}
+	return ValidateABA(d.ABA)
+}
+
+// CABankAccount holds the details of a Canadian bank account.
+type CABankAccount struct {
+	BankName          string
+	InstitutionNumber string
+	BranchCode        string
+	AccountNumber     string
+}
+
+func (d *CABankAccount) Type() string { return accountTypes[CA] }
+
+func (d *CABankAccount) validate() error {
+	if d.BankName == "" || d.InstitutionNumber == "" || d.BranchCode == "" ||
+		d.AccountNumber == "" {
+		return errors.New("missing full CA information")
+	}
+	return firstError(ValidateCAInstitutionNumber(d.InstitutionNumber),
+		ValidateCABranchCode(d.BranchCode))
 }
 
+// OtherBankAccount holds the details of a bank account from any other
+// country.
+type OtherBankAccount struct {
+	Country       string
+	BIC           string
+	AccountNumber string
+}
+
+func (d *OtherBankAccount) Type() string { return accountTypes[OTHER] }
+
+func (d *OtherBankAccount) validate() error {
+	if d.Country == "" || d.BIC == "" || d.AccountNumber == "" {
+		return errors.New("missing full OTHER information")
+	}
+	if !isCountryCode(d.Country) {
+		return fmt.Errorf("invalid country code %q", d.Country)
+	}
+	return ValidateBIC(d.BIC)
+}
+
+// Fields specific to account types.
+var bankAccountDetailsFields = []string{"IBAN", "BIC", "AccountNumber", "SortCode",
+	"ABA", "DepositAccountType", "BankName", "InstitutionNumber", "BranchCode", "Country"}
+
 func (b *BankAccount) String() string {
 	return struct2string(b)
 }
 
+// Details returns the fields specific to the account's type, or nil for an
+// unknown type. As it only relies on the Type field, it works for fetched
+// accounts too.
+func (b *BankAccount) Details() BankAccountDetails {
+	switch b.Type {
+	case accountTypes[IBAN]:
+		return &IBANBankAccount{IBAN: b.IBAN, BIC: b.BIC}
+	case accountTypes[GB]:
+		return &GBBankAccount{AccountNumber: b.AccountNumber, SortCode: b.SortCode}
+	case accountTypes[US]:
+		return &USBankAccount{AccountNumber: b.AccountNumber, ABA: b.ABA,
+			DepositAccountType: b.DepositAccountType}
+	case accountTypes[CA]:
+		return &CABankAccount{BankName: b.BankName, InstitutionNumber: b.InstitutionNumber,
+			BranchCode: b.BranchCode, AccountNumber: b.AccountNumber}
+	case accountTypes[OTHER]:
+		return &OtherBankAccount{Country: b.Country, BIC: b.BIC, AccountNumber: b.AccountNumber}
+	}
+	return nil
+}
+
+// SetDetails sets the account's type and the fields specific to it.
+func (b *BankAccount) SetDetails(d BankAccountDetails) {
+	b.Type = d.Type()
+	b.IBAN, b.BIC, b.AccountNumber, b.SortCode, b.ABA, b.DepositAccountType = "", "", "", "", "", ""
+	b.BankName, b.InstitutionNumber, b.BranchCode, b.Country = "", "", "", ""
+	switch v := d.(type) {
+	case *IBANBankAccount:
+		b.IBAN, b.BIC = v.IBAN, v.BIC
+	case *GBBankAccount:
+		b.AccountNumber, b.SortCode = v.AccountNumber, v.SortCode
+	case *USBankAccount:
+		b.AccountNumber, b.ABA, b.DepositAccountType = v.AccountNumber, v.ABA, v.DepositAccountType
+	case *CABankAccount:
+		b.BankName, b.InstitutionNumber, b.BranchCode, b.AccountNumber =
+			v.BankName, v.InstitutionNumber, v.BranchCode, v.AccountNumber
+	case *OtherBankAccount:
+		b.Country, b.BIC, b.AccountNumber = v.Country, v.BIC, v.AccountNumber
+	}
+}
+
 // NewBankAccount creates a new bank account. Note that depending on the account's
 // type, some fields of the newly BankAccount instance must be filled (they are
-// required) before a call to Save().
+// required) before a call to Save(), either directly or with SetDetails().
 //
 // See http://docs.mangopay.com/api-references/bank-accounts/
-func (m *MangoPay) NewBankAccount(user Consumer, ownerName, ownerAddress string, t AccountType) (*BankAccount, error) {
+func (m *MangoPay) NewBankAccount(user Consumer, ownerName string, ownerAddress *Address, t AccountType) (*BankAccount, error) {
 	id := consumerId(user)
 	if id == "" {
 		return nil, errors.New("user has empty Id")
@@ -88,13 +243,33 @@ func (m *MangoPay) NewBankAccount(user Consumer, ownerName, ownerAddress string,
 		OwnerAddress: ownerAddress,
 		UserId:       id,
 		service:      m,
-		atype:        t,
 	}
 	return b, nil
 }
 
 // Save sends the HTTP query to create the bank account.
 func (b *BankAccount) Save() error {
+	if b.OwnerAddress == nil {
+		return errors.New("missing owner address")
+	}
+	if err := b.OwnerAddress.Validate(); err != nil {
+		return err
+	}
+	switch b.Type {
+	case accountTypes[IBAN]:
+		b.IBAN = normalizeIBAN(b.IBAN)
+	case accountTypes[GB]:
+		b.SortCode = normalizeSortCode(b.SortCode)
+	}
+	b.BIC = normalizeBIC(b.BIC)
+	details := b.Details()
+	if details == nil {
+		return fmt.Errorf("unknown bank account type %q", b.Type)
+	}
+	if err := details.validate(); err != nil {
+		return err
+	}
+
 	data := JsonObject{}
 	j, err := json.Marshal(b)
 	if err != nil {
@@ -104,44 +279,18 @@ func (b *BankAccount) Save() error {
 		return err
 	}
 
-	// Data fields to remove before sending the HTTP request.
-	ignore := []string{"Id", "CreationDate"}
-	switch b.atype {
-	case IBAN:
-		if b.IBAN == "" {
-			return errors.New("missing full IBAN information")
-		}
-		ignore = append(ignore, "AccountNumber", "SortCode", "ABA", "BankName",
-			"InstitutionNumber", "BranchCode", "Country", "BIC")
-	case GB:
-		if b.AccountNumber == "" || b.SortCode == "" {
-			return errors.New("missing full GB information")
-		}
-		ignore = append(ignore, "IBAN", "BIC", "ABA", "BankName",
-			"InstitutionNumber", "BranchCode", "Country")
-	case US:
-		if b.AccountNumber == "" || b.ABA == "" {
-			return errors.New("missing full US information")
-		}
-		ignore = append(ignore, "IBAN", "BIC", "SortCode", "BankName",
-			"InstitutionNumber", "BranchCode", "Country")
-	case CA:
-		if b.BankName == "" || b.InstitutionNumber == "" || b.BranchCode == "" ||
-			b.AccountNumber == "" {
-			return errors.New("missing full CA information")
-		}
-		ignore = append(ignore, "IBAN", "BIC", "SortCode", "ABA", "Country")
-	case OTHER:
-		if b.AccountNumber == "" {
-			return errors.New("missing full OTHER information")
-		}
-		ignore = append(ignore, "IBAN", "ABA", "SortCode", "BankName",
-			"InstitutionNumber", "BranchCode", "BIC")
-	}
-
-	for _, field := range ignore {
+	// Data fields to remove before sending the HTTP request. Only the
+	// fields specific to the account's type are sent.
+	for _, field := range append([]string{"Id", "CreationDate", "Active"}, bankAccountDetailsFields...) {
 		delete(data, field)
 	}
+	j, err = json.Marshal(details)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(j, &data); err != nil {
+		return err
+	}
 
 	ba, err := b.service.anyRequest(new(BankAccount), actionCreateBankAccount, data)
 	if err != nil {
@@ -154,6 +303,37 @@ func (b *BankAccount) Save() error {
 	return nil
 }
 
+// Deactivate deactivates the bank account. A deactivated account can't be
+// used for payouts anymore, and can't be reactivated.
+func (b *BankAccount) Deactivate() error {
+	if b.Id == "" {
+		return errors.New("bank account has empty Id")
+	}
+	ba, err := b.service.anyRequest(new(BankAccount), actionEditBankAccount,
+		JsonObject{"Id": b.Id, "UserId": b.UserId, "Active": false})
+	if err != nil {
+		return err
+	}
+	serv := b.service
+	*b = *(ba.(*BankAccount))
+	b.service = serv
+	return nil
+}
+
+// Transactions finds the transactions of the bank account, i.e its payouts.
+// filter is optional.
+func (b *BankAccount) Transactions(filter *TransactionFilter) (TransferList, error) {
+	if b.Id == "" {
+		return nil, errors.New("bank account has empty Id")
+	}
+	trs, err := b.service.anyQueryRequest(new(TransferList), actionFetchBankAccountTransactions,
+		JsonObject{"Id": b.Id}, filter.values())
+	if err != nil {
+		return nil, err
+	}
+	return *(trs.(*TransferList)), nil
+}
+
 // BankAccount returns a user's bank account.
 func (m *MangoPay) BankAccount(user Consumer, id string) (*BankAccount, error) {
 	userId := consumerId(user)
@@ -165,7 +345,9 @@ func (m *MangoPay) BankAccount(user Consumer, id string) (*BankAccount, error) {
 	if err != nil {
 		return nil, err
 	}
-	return w.(*BankAccount), nil
+	b := w.(*BankAccount)
+	b.service = m
+	return b, nil
 }
 
 // BankAccounts finds all user's bank accounts.
@@ -178,5 +360,9 @@ func (m *MangoPay) BankAccounts(user Consumer) (BankAccountList, error) {
 	if err != nil {
 		return nil, err
 	}
-	return *(accs.(*BankAccountList)), nil
+	casted := *(accs.(*BankAccountList))
+	for _, b := range casted {
+		b.service = m
+	}
+	return casted, nil
 }
@@ -9,6 +9,13 @@ const (
 	testBIC  = "CRLYFRPP"
 )
 
+var testAddress = &Address{
+	AddressLine1: "1 rue de la Paix",
+	City:         "Paris",
+	PostalCode:   "75002",
+	Country:      "FR",
+}
+
 func TestBankAccount_Save(test *testing.T) {
 	serv := newTestService(test)
 	user := createTestUser(serv)
@@ -24,7 +31,7 @@ func TestBankAccount_Save(test *testing.T) {
 }
 
 func createTestBankAccount(test *testing.T, serv *MangoPay, user *NaturalUser) *BankAccount {
-	acc, err := serv.NewBankAccount(user, user.FirstName, "one great place", IBAN)
+	acc, err := serv.NewBankAccount(user, user.FirstName, testAddress, IBAN)
 	if err != nil {
 		test.Fatal("Unable to create BankAccount", err.Error())
 	}
@@ -35,3 +42,40 @@ func createTestBankAccount(test *testing.T, serv *MangoPay, user *NaturalUser) *
 
 	return acc
 }
+
+func TestBankAccountDetails(test *testing.T) {
+	acc := &BankAccount{OwnerAddress: testAddress}
+	acc.SetDetails(&USBankAccount{AccountNumber: "11696419", ABA: "071000288",
+		DepositAccountType: DepositAccountTypeSavings})
+	if acc.Type != "US" || acc.ABA != "071000288" {
+		test.Fatalf("details not set: %v", acc)
+	}
+	us, ok := acc.Details().(*USBankAccount)
+	if !ok || us.DepositAccountType != DepositAccountTypeSavings {
+		test.Errorf("expected US details, got %v", acc.Details())
+	}
+
+	// Validation relies on the type only, i.e for fetched accounts.
+	fetched := &BankAccount{Type: "GB", OwnerAddress: testAddress, AccountNumber: "1"}
+	if err := fetched.Save(); err == nil {
+		test.Error("expected an error for a GB account without sort code")
+	}
+	acc.DepositAccountType = "BROKERAGE"
+	if err := acc.Save(); err == nil {
+		test.Error("expected an error for an invalid deposit account type")
+	}
+
+	for _, other := range []*OtherBankAccount{
+		{BIC: testBIC, AccountNumber: "11696419"},
+		{Country: "ZZ", BIC: testBIC, AccountNumber: "11696419"},
+		{Country: "MX", AccountNumber: "11696419"},
+	} {
+		if err := other.validate(); err == nil {
+			test.Errorf("expected an error for OTHER details %v", other)
+		}
+	}
+	other := &OtherBankAccount{Country: "MX", BIC: testBIC, AccountNumber: "11696419"}
+	if err := other.validate(); err != nil {
+		test.Errorf("valid OTHER details rejected: %v", err)
+	}
+}
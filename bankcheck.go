@@ -0,0 +1,185 @@
+// Copyright 2014 Mathias Monnerville. All rights reserved.
+// Use of this source code is governed by a GPL
+// license that can be found in the LICENSE file.
+
+package mango
+
+import (
+	"fmt"
+	"strings"
+)
+
+// IBAN lengths by country code.
+var ibanLengths = map[string]int{
+	"AD": 24, "AE": 23, "AL": 28, "AT": 20, "AZ": 28, "BA": 20, "BE": 16,
+	"BG": 22, "BH": 22, "BI": 27, "BR": 29, "BY": 28, "CH": 21, "CR": 22,
+	"CY": 28, "CZ": 24, "DE": 22, "DJ": 27, "DK": 18, "DO": 28, "EE": 20,
+	"EG": 29, "ES": 24, "FI": 18, "FK": 18, "FO": 18, "FR": 27, "GB": 22,
+	"GE": 22, "GI": 23, "GL": 18, "GR": 27, "GT": 28, "HR": 21, "HU": 28,
+	"IE": 22, "IL": 23, "IQ": 23, "IS": 26, "IT": 27, "JO": 30, "KW": 30,
+	"KZ": 20, "LB": 28, "LC": 32, "LI": 21, "LT": 20, "LU": 20, "LV": 21,
+	"LY": 25, "MC": 27, "MD": 24, "ME": 22, "MK": 19, "MN": 20, "MR": 27,
+	"MT": 31, "MU": 30, "NI": 28, "NL": 18, "NO": 15, "OM": 23, "PK": 24,
+	"PL": 28, "PS": 29, "PT": 25, "QA": 29, "RO": 24, "RS": 22, "RU": 33,
+	"SA": 24, "SC": 31, "SD": 18, "SE": 24, "SI": 19, "SK": 24, "SM": 27,
+	"SO": 23, "ST": 25, "SV": 28, "TL": 23, "TN": 24, "TR": 26, "UA": 29,
+	"VA": 22, "VG": 24, "XK": 20, "YE": 30,
+}
+
+// ValidateIBAN checks the country, the length and the mod-97 checksum of an
+// IBAN. Spaces are ignored, so that IBANs in print format are accepted. The
+// length is not checked for countries missing from the IBAN lengths table.
+func ValidateIBAN(iban string) error {
+	iban = normalizeIBAN(iban)
+	if len(iban) < 4 {
+		return fmt.Errorf("invalid IBAN %q: too short", iban)
+	}
+	length, ok := ibanLengths[iban[:2]]
+	if !ok && !isBankCountryCode(iban[:2]) {
+		return fmt.Errorf("invalid IBAN %q: unknown country %s", iban, iban[:2])
+	}
+	if ok && len(iban) != length {
+		return fmt.Errorf("invalid IBAN %q: expected %d characters for %s, got %d",
+			iban, length, iban[:2], len(iban))
+	}
+	if !isDigits(iban[2:4]) {
+		return fmt.Errorf("invalid IBAN %q: bad check digits", iban)
+	}
+	// Move the first 4 characters to the end, convert letters to numbers
+	// (A=10, ..., Z=35) and compute the remainder digit by digit.
+	rem := 0
+	for _, c := range iban[4:] + iban[:4] {
+		switch {
+		case c >= '0' && c <= '9':
+			rem = (rem*10 + int(c-'0')) % 97
+		case c >= 'A' && c <= 'Z':
+			rem = (rem*100 + int(c-'A') + 10) % 97
+		default:
+			return fmt.Errorf("invalid IBAN %q: bad character %q", iban, c)
+		}
+	}
+	if rem != 1 {
+		return fmt.Errorf("invalid IBAN %q: bad checksum", iban)
+	}
+	return nil
+}
+
+// normalizeBIC uppercases a BIC and removes its spaces.
+func normalizeBIC(bic string) string {
+	return strings.ToUpper(strings.Replace(bic, " ", "", -1))
+}
+
+// isBankCountryCode returns true if code is a country code used in IBANs
+// and BICs, i.e an ISO 3166-1 code or Kosovo's XK.
+func isBankCountryCode(code string) bool {
+	return code == "XK" || isCountryCode(code)
+}
+
+// normalizeSortCode removes the dashes and spaces of a sort code in print
+// format, i.e 20-00-00.
+func normalizeSortCode(code string) string {
+	return strings.NewReplacer("-", "", " ", "").Replace(code)
+}
+
+// normalizeIBAN turns an IBAN in print format into its electronic format.
+func normalizeIBAN(iban string) string {
+	return strings.ToUpper(strings.Replace(iban, " ", "", -1))
+}
+
+// ValidateBIC checks the structure of a BIC (SWIFT code): a 4 letters bank
+// code, a country code, a 2 characters location code and an optional 3
+// characters branch code. Spaces and case are ignored.
+func ValidateBIC(bic string) error {
+	bic = normalizeBIC(bic)
+	if len(bic) != 8 && len(bic) != 11 {
+		return fmt.Errorf("invalid BIC %q: must be 8 or 11 characters long", bic)
+	}
+	for _, c := range bic[:4] {
+		if c < 'A' || c > 'Z' {
+			return fmt.Errorf("invalid BIC %q: bad bank code", bic)
+		}
+	}
+	if !isBankCountryCode(bic[4:6]) {
+		return fmt.Errorf("invalid BIC %q: unknown country %s", bic, bic[4:6])
+	}
+	for _, c := range bic[6:] {
+		if !(c >= 'A' && c <= 'Z' || c >= '0' && c <= '9') {
+			return fmt.Errorf("invalid BIC %q: bad location or branch code", bic)
+		}
+	}
+	return nil
+}
+
+// ValidateSortCode checks that a UK sort code has 6 digits. Dashes and
+// spaces are ignored.
+func ValidateSortCode(code string) error {
+	code = normalizeSortCode(code)
+	if len(code) != 6 || !isDigits(code) {
+		return fmt.Errorf("invalid sort code %q: must be 6 digits", code)
+	}
+	return nil
+}
+
+// ValidateGBAccountNumber checks that a UK account number has 8 digits.
+func ValidateGBAccountNumber(number string) error {
+	if len(number) != 8 || !isDigits(number) {
+		return fmt.Errorf("invalid account number %q: must be 8 digits", number)
+	}
+	return nil
+}
+
+// ValidateABA checks the format and the checksum of a US ABA routing
+// number.
+func ValidateABA(aba string) error {
+	if len(aba) != 9 || !isDigits(aba) {
+		return fmt.Errorf("invalid ABA %q: must be 9 digits", aba)
+	}
+	sum := 0
+	for k, c := range aba {
+		sum += int(c-'0') * []int{3, 7, 1}[k%3]
+	}
+	if sum%10 != 0 {
+		return fmt.Errorf("invalid ABA %q: bad checksum", aba)
+	}
+	return nil
+}
+
+// ValidateCAInstitutionNumber checks that a Canadian institution number has
+// 3 digits.
+func ValidateCAInstitutionNumber(number string) error {
+	if len(number) != 3 || !isDigits(number) {
+		return fmt.Errorf("invalid institution number %q: must be 3 digits", number)
+	}
+	return nil
+}
+
+// ValidateCABranchCode checks that a Canadian branch (transit) number has 5
+// digits.
+func ValidateCABranchCode(code string) error {
+	if len(code) != 5 || !isDigits(code) {
+		return fmt.Errorf("invalid branch code %q: must be 5 digits", code)
+	}
+	return nil
+}
+
+// firstError returns the first non-nil error.
+func firstError(errs ...error) error {
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,44 @@
+package mango
+
+import "testing"
+
+func TestBankValidators(test *testing.T) {
+	for _, tt := range []struct {
+		name  string
+		check func(string) error
+		value string
+		valid bool
+	}{
+		{"IBAN", ValidateIBAN, testIBAN, true},
+		{"IBAN", ValidateIBAN, "GB82 WEST 1234 5698 7654 32", true},
+		{"IBAN", ValidateIBAN, "GB82WEST12345698765433", false},
+		{"IBAN", ValidateIBAN, "FR30200410101245307", false},
+		{"IBAN", ValidateIBAN, "ZZ82WEST12345698765432", false},
+		{"IBAN", ValidateIBAN, "RU0204452560040702810412345678901", true},
+		{"IBAN", ValidateIBAN, "HN09ABCD00000000000000123456", true},
+		{"IBAN", ValidateIBAN, "HN10ABCD00000000000000123456", false},
+		{"BIC", ValidateBIC, testBIC, true},
+		{"BIC", ValidateBIC, "DEUTDEFF500", true},
+		{"BIC", ValidateBIC, "DEUTZZFF", false},
+		{"BIC", ValidateBIC, "DEU1DEFF", false},
+		{"BIC", ValidateBIC, "deutdeff", true},
+		{"BIC", ValidateBIC, "DEUT DE FF 500", true},
+		{"BIC", ValidateBIC, "RBKOXKPR", true},
+		{"sort code", ValidateSortCode, "200000", true},
+		{"sort code", ValidateSortCode, "20-00-00", true},
+		{"sort code", ValidateSortCode, "20 00 00", true},
+		{"sort code", ValidateSortCode, "20-00-0", false},
+		{"GB account", ValidateGBAccountNumber, "55779911", true},
+		{"GB account", ValidateGBAccountNumber, "5577991", false},
+		{"ABA", ValidateABA, "071000288", true},
+		{"ABA", ValidateABA, "071000289", false},
+		{"CA institution", ValidateCAInstitutionNumber, "614", true},
+		{"CA institution", ValidateCAInstitutionNumber, "6140", false},
+		{"CA branch", ValidateCABranchCode, "00152", true},
+		{"CA branch", ValidateCABranchCode, "0015A", false},
+	} {
+		if err := tt.check(tt.value); (err == nil) != tt.valid {
+			test.Errorf("%s %q: expected valid=%v, got %v", tt.name, tt.value, tt.valid, err)
+		}
+	}
+}
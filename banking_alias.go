@@ -5,6 +5,11 @@ import (
 	"errors"
 )
 
+const (
+	BankingAliasTypeIBAN = "IBAN"
+	BankingAliasTypeGB   = "GB"
+)
+
 // List of banking aliases.
 type BankingAliasList []*BankingAlias
 
@@ -13,25 +18,37 @@ type BankingAlias struct {
 
 	CreditedUserId string
 	WalletId       string
-	Type           string // IBAN
+	Type           string // IBAN or GB
 	Country        string
 	OwnerName      string
 	Active         bool
-	IBAN           string
-	BIC            string
+	// For IBAN type
+	IBAN string
+	BIC  string
+	// For GB type
+	AccountNumber string
+	SortCode      string
 
 	service *MangoPay
 }
 
-// NewBankingAlias creates a new banking alias.
+func (b *BankingAlias) String() string {
+	return struct2string(b)
+}
+
+// NewBankingAlias creates a new IBAN banking alias.
 //
 // See https://docs.mangopay.com/endpoints/v2.01/banking-aliases
-func (m *MangoPay) NewBankingAlias(wallet Wallet, ownerName string, country string) (*BankingAlias, error) {
+func (m *MangoPay) NewBankingAlias(wallet *Wallet, ownerName string, country string) (*BankingAlias, error) {
+	if wallet == nil {
+		return nil, errors.New("nil wallet")
+	}
 	if wallet.Id == "" {
 		return nil, errors.New("wallet has empty Id")
 	}
 	b := &BankingAlias{
 		ProcessIdent: ProcessIdent{},
+		Type:         BankingAliasTypeIBAN,
 		OwnerName:    ownerName,
 		Country:      country,
 		WalletId:     wallet.Id,
@@ -40,8 +57,36 @@ func (m *MangoPay) NewBankingAlias(wallet Wallet, ownerName string, country stri
 	return b, nil
 }
 
+// NewGBBankingAlias creates a new UK banking alias, with an account number
+// and a sort code.
+func (m *MangoPay) NewGBBankingAlias(wallet *Wallet, ownerName string) (*BankingAlias, error) {
+	if wallet == nil {
+		return nil, errors.New("nil wallet")
+	}
+	if wallet.Id == "" {
+		return nil, errors.New("wallet has empty Id")
+	}
+	b := &BankingAlias{
+		Type:      BankingAliasTypeGB,
+		OwnerName: ownerName,
+		Country:   "GB",
+		WalletId:  wallet.Id,
+		service:   m,
+	}
+	return b, nil
+}
+
 // Save sends the HTTP query to create the bank alias.
 func (b *BankingAlias) Save() error {
+	action := actionCreateBankingAlias
+	switch b.Type {
+	case "", BankingAliasTypeIBAN:
+	case BankingAliasTypeGB:
+		action = actionCreateGBBankingAlias
+	default:
+		return errors.New("unknown banking alias type " + b.Type)
+	}
+
 	data := JsonObject{}
 	j, err := json.Marshal(b)
 	if err != nil {
@@ -52,11 +97,15 @@ func (b *BankingAlias) Save() error {
 	}
 
 	// Data fields to remove before sending the HTTP request.
-	for _, field := range []string{"Id", "CreationDate"} {
+	for _, field := range []string{"Id", "CreationDate", "Type",
+		"Active", "IBAN", "BIC", "AccountNumber", "SortCode"} {
 		delete(data, field)
 	}
+	if b.CreditedUserId == "" {
+		delete(data, "CreditedUserId")
+	}
 
-	ba, err := b.service.anyRequest(new(BankingAlias), actionCreateBankingAlias, data)
+	ba, err := b.service.anyRequest(new(BankingAlias), action, data)
 	if err != nil {
 		return err
 	}
@@ -67,6 +116,105 @@ func (b *BankingAlias) Save() error {
 	return nil
 }
 
+// Deactivate deactivates the banking alias. Bank wires sent to it are then
+// rejected until it is activated again.
+func (b *BankingAlias) Deactivate() error {
+	return b.setActive(false)
+}
+
+// Activate activates a deactivated banking alias.
+func (b *BankingAlias) Activate() error {
+	return b.setActive(true)
+}
+
+func (b *BankingAlias) setActive(active bool) error {
+	if b.Id == "" {
+		return errors.New("banking alias has empty Id")
+	}
+	ba, err := b.service.anyRequest(new(BankingAlias), actionEditBankingAlias,
+		JsonObject{"BankingAliasId": b.Id, "Active": active})
+	if err != nil {
+		return err
+	}
+	serv := b.service
+	*b = *(ba.(*BankingAlias))
+	b.service = serv
+	return nil
+}
+
+// List of payins received on banking aliases.
+type BankingAliasPayInList []*BankingAliasPayIn
+
+// BankingAliasPayIn is a bank wire received on a banking alias.
+type BankingAliasPayIn struct {
+	PayIn
+	BankingAliasId     string
+	WireReference      string
+	DebitedBankAccount struct {
+		OwnerName     string
+		Type          string
+		IBAN          string
+		BIC           string
+		AccountNumber string
+		SortCode      string
+	}
+}
+
+func (p *BankingAliasPayIn) String() string {
+	return struct2string(p)
+}
+
+// PayIns finds the bank wires received on the banking alias. filter is
+// optional and applies to the transactions of the alias' wallet; its Type
+// is always PAYIN and all pages are read, from filter's Page on.
+//
+// Wallet transactions do not tell which banking alias a bank wire came
+// from, so each BANK_WIRE payin is fetched to check it.
+func (b *BankingAlias) PayIns(filter *TransactionFilter) (BankingAliasPayInList, error) {
+	if b.Id == "" || b.WalletId == "" {
+		return nil, errors.New("banking alias has empty Id or WalletId")
+	}
+	f := TransactionFilter{}
+	if filter != nil {
+		f = *filter
+	}
+	f.Type = "PAYIN"
+	if f.Page == 0 {
+		f.Page = 1
+	}
+	if f.PerPage == 0 {
+		f.PerPage = 100
+	}
+	payins := BankingAliasPayInList{}
+	for ; ; f.Page++ {
+		list, err := b.service.anyQueryRequest(new(BankingAliasPayInList), actionFetchWalletTransactions,
+			JsonObject{"Id": b.WalletId}, f.values())
+		if err != nil {
+			return nil, err
+		}
+		page := *(list.(*BankingAliasPayInList))
+		for _, tr := range page {
+			if tr.PaymentType != "BANK_WIRE" {
+				continue
+			}
+			p, err := b.service.anyRequest(new(BankingAliasPayIn), actionFetchPayIn,
+				JsonObject{"Id": tr.Id})
+			if err != nil {
+				return nil, err
+			}
+			payin := p.(*BankingAliasPayIn)
+			if payin.BankingAliasId == b.Id {
+				payin.service = b.service
+				payins = append(payins, payin)
+			}
+		}
+		if len(page) < f.PerPage {
+			break
+		}
+	}
+	return payins, nil
+}
+
 // BankingAlias returns a user's banking alias.
 func (m *MangoPay) BankingAlias(id string) (*BankingAlias, error) {
 	w, err := m.anyRequest(new(BankingAlias), actionFetchBankingAlias,
@@ -74,18 +222,27 @@ func (m *MangoPay) BankingAlias(id string) (*BankingAlias, error) {
 	if err != nil {
 		return nil, err
 	}
-	return w.(*BankingAlias), nil
+	b := w.(*BankingAlias)
+	b.service = m
+	return b, nil
 }
 
-// BankingAliases finds all user's bank aliases.
-func (m *MangoPay) BankingAliases(wallet Wallet) (BankAccountList, error) {
+// BankingAliases finds all wallet's bank aliases.
+func (m *MangoPay) BankingAliases(wallet *Wallet) (BankingAliasList, error) {
+	if wallet == nil {
+		return nil, errors.New("nil wallet")
+	}
 	if wallet.Id == "" {
 		return nil, errors.New("wallet has empty Id")
 	}
-	accs, err := m.anyRequest(new(BankingAliasList), actionFetchBankingAliases,
+	list, err := m.anyRequest(new(BankingAliasList), actionFetchBankingAliases,
 		JsonObject{"WalletId": wallet.Id})
 	if err != nil {
 		return nil, err
 	}
-	return *(accs.(*BankAccountList)), nil
+	casted := *(list.(*BankingAliasList))
+	for _, b := range casted {
+		b.service = m
+	}
+	return casted, nil
 }
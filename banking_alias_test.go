@@ -0,0 +1,93 @@
+package mango
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+func TestNewGBBankingAlias(test *testing.T) {
+	m := new(MangoPay)
+	b, err := m.NewGBBankingAlias(m.WalletRef("1"), "Sergey")
+	if err != nil {
+		test.Fatal(err)
+	}
+	if b.Type != BankingAliasTypeGB || b.WalletId != "1" {
+		test.Errorf("unexpected banking alias: %v", b)
+	}
+	if _, err := m.NewGBBankingAlias(nil, "Sergey"); err == nil {
+		test.Error("expected an error for a nil wallet")
+	}
+	b.Type = "US"
+	if err := b.Save(); err == nil {
+		test.Error("expected an error for an unknown banking alias type")
+	}
+	if _, err := b.PayIns(nil); err == nil {
+		test.Error("expected an error for an unsaved banking alias")
+	}
+}
+
+func TestBankingAliasSaveBody(test *testing.T) {
+	var body JsonObject
+	m := newFakeService(test, func(w http.ResponseWriter, req *http.Request) {
+		if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+			test.Error(err)
+		}
+		w.Write([]byte(`{"Id": "2", "WalletId": "1", "CreditedUserId": "3", "Type": "IBAN", "Active": true}`))
+	})
+	b, err := m.NewBankingAlias(m.WalletRef("1"), "Sergey", "FR")
+	if err != nil {
+		test.Fatal(err)
+	}
+	b.CreditedUserId = "3"
+	if err := b.Save(); err != nil {
+		test.Fatal(err)
+	}
+	if body["CreditedUserId"] != "3" {
+		test.Errorf("expected CreditedUserId to be sent, got %v", body)
+	}
+	for _, field := range []string{"Id", "Active", "IBAN", "Type"} {
+		if _, ok := body[field]; ok {
+			test.Errorf("read-only field %s must not be sent", field)
+		}
+	}
+	if b.Id != "2" || b.service != m {
+		test.Errorf("unexpected banking alias: %v", b)
+	}
+}
+
+func TestBankingAliasPayIns(test *testing.T) {
+	m := newFakeService(test, func(w http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/v2/test/wallets/1/transactions":
+			if req.URL.Query().Get("Type") != "PAYIN" {
+				test.Errorf("expected PAYIN transactions, got %s", req.URL.RawQuery)
+			}
+			w.Write([]byte(`[
+				{"Id": "p1", "Type": "PAYIN", "PaymentType": "BANK_WIRE"},
+				{"Id": "p2", "Type": "PAYIN", "PaymentType": "CARD"},
+				{"Id": "p3", "Type": "PAYIN", "PaymentType": "BANK_WIRE"}
+			]`))
+		case "/v2/test/payins/p1":
+			w.Write([]byte(`{"Id": "p1", "PaymentType": "BANK_WIRE", "BankingAliasId": "2",
+				"WireReference": "ref", "DebitedFunds": {"Currency": "EUR", "Amount": 1000}}`))
+		case "/v2/test/payins/p3":
+			w.Write([]byte(`{"Id": "p3", "PaymentType": "BANK_WIRE", "BankingAliasId": "other"}`))
+		default:
+			test.Errorf("unexpected request %s", req.URL.Path)
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	})
+	b := &BankingAlias{WalletId: "1", service: m}
+	b.Id = "2"
+	payins, err := b.PayIns(nil)
+	if err != nil {
+		test.Fatal(err)
+	}
+	if len(payins) != 1 || payins[0].Id != "p1" || payins[0].WireReference != "ref" {
+		test.Fatalf("expected payin p1 only, got %v", payins)
+	}
+	if payins[0].DebitedFunds.Amount != 1000 {
+		test.Errorf("unexpected debited funds: %v", payins[0].DebitedFunds)
+	}
+}
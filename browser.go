@@ -0,0 +1,118 @@
+// Copyright 2014 Mathias Monnerville. All rights reserved.
+// Use of this source code is governed by a GPL
+// license that can be found in the LICENSE file.
+
+package mango
+
+import (
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// BrowserInfo describes the browser used by a user to pay. It is required
+// by 3DS2 to assess the risk of a card payment.
+//
+// See https://docs.mangopay.com/guide/3ds2-integration
+type BrowserInfo struct {
+	AcceptHeader      string
+	JavaEnabled       bool
+	Language          string
+	ColorDepth        int
+	ScreenHeight      int
+	ScreenWidth       int
+	TimeZoneOffset    string // Minutes from UTC, i.e "+60"
+	UserAgent         string
+	JavascriptEnabled bool
+}
+
+func (b *BrowserInfo) String() string {
+	return struct2string(b)
+}
+
+// NewBrowserInfo builds browser info from an incoming HTTP request.
+//
+// AcceptHeader, UserAgent and Language are read from the request headers.
+// The other fields can only be collected by the browser itself; they are
+// read from the request's URL query if present, using the field names as
+// keys (i.e "ColorDepth", "ScreenHeight", "TimeZoneOffset" etc.).
+// JavascriptEnabled is true when those values were sent. The request body
+// is not read, so that the caller can still decode it.
+func NewBrowserInfo(req *http.Request) *BrowserInfo {
+	b := &BrowserInfo{
+		AcceptHeader: req.Header.Get("Accept"),
+		UserAgent:    req.Header.Get("User-Agent"),
+		Language:     "en",
+	}
+	if lang := req.Header.Get("Accept-Language"); lang != "" {
+		// Keep the preferred language tag only, i.e "fr-FR" in
+		// "fr-FR,fr;q=0.9,en;q=0.8".
+		lang = strings.SplitN(lang, ",", 2)[0]
+		lang = strings.SplitN(lang, ";", 2)[0]
+		b.Language = strings.TrimSpace(lang)
+	}
+	q := req.URL.Query()
+	if q.Get("ColorDepth") != "" {
+		b.JavascriptEnabled = true
+		b.ColorDepth, _ = strconv.Atoi(q.Get("ColorDepth"))
+		b.ScreenHeight, _ = strconv.Atoi(q.Get("ScreenHeight"))
+		b.ScreenWidth, _ = strconv.Atoi(q.Get("ScreenWidth"))
+		b.TimeZoneOffset = q.Get("TimeZoneOffset")
+		b.JavaEnabled, _ = strconv.ParseBool(q.Get("JavaEnabled"))
+	}
+	return b
+}
+
+// IpAddressFromRequest returns the IP address of the user who sent req.
+//
+// By default, the address of the connection's peer (req.RemoteAddr) is
+// used: the X-Forwarded-For header is set by the client and can't be
+// trusted. When the server is behind proxies, list their IP addresses or
+// CIDR ranges in trustedProxies. If the peer is one of them, the
+// X-Forwarded-For hops are then read from right to left and the first one
+// not belonging to a trusted proxy is returned.
+func IpAddressFromRequest(req *http.Request, trustedProxies ...string) string {
+	ip, _, err := net.SplitHostPort(req.RemoteAddr)
+	if err != nil {
+		ip = req.RemoteAddr
+	}
+	if len(trustedProxies) == 0 || !isTrustedProxy(ip, trustedProxies) {
+		return ip
+	}
+	hops := []string{}
+	for _, h := range req.Header.Values("X-Forwarded-For") {
+		hops = append(hops, strings.Split(h, ",")...)
+	}
+	for k := len(hops) - 1; k >= 0; k-- {
+		hop := strings.TrimSpace(hops[k])
+		if net.ParseIP(hop) == nil {
+			// Not set by a trusted proxy: stop at the last valid hop.
+			break
+		}
+		ip = hop
+		if !isTrustedProxy(hop, trustedProxies) {
+			break
+		}
+	}
+	return ip
+}
+
+// isTrustedProxy returns true if ip matches one of the proxies' IP
+// addresses or CIDR ranges.
+func isTrustedProxy(ip string, proxies []string) bool {
+	addr := net.ParseIP(ip)
+	if addr == nil {
+		return false
+	}
+	for _, p := range proxies {
+		if _, network, err := net.ParseCIDR(p); err == nil {
+			if network.Contains(addr) {
+				return true
+			}
+		} else if proxy := net.ParseIP(p); proxy != nil && proxy.Equal(addr) {
+			return true
+		}
+	}
+	return false
+}
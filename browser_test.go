@@ -0,0 +1,73 @@
+package mango
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestNewBrowserInfo(test *testing.T) {
+	query := url.Values{
+		"ColorDepth":     []string{"24"},
+		"ScreenHeight":   []string{"1080"},
+		"ScreenWidth":    []string{"1920"},
+		"TimeZoneOffset": []string{"+60"},
+		"JavaEnabled":    []string{"false"},
+	}
+	req, err := http.NewRequest("POST", "http://test.de/pay?"+query.Encode(),
+		strings.NewReader(`{"Amount": 1000}`))
+	if err != nil {
+		test.Fatal(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "text/html")
+	req.Header.Set("Accept-Language", "fr-FR,fr;q=0.9,en;q=0.8")
+	req.Header.Set("User-Agent", "Mozilla/5.0")
+	req.Header.Set("X-Forwarded-For", "203.0.113.7, 10.0.0.1")
+	req.RemoteAddr = "10.0.0.1:4242"
+
+	b := NewBrowserInfo(req)
+	expected := BrowserInfo{
+		AcceptHeader:      "text/html",
+		Language:          "fr-FR",
+		ColorDepth:        24,
+		ScreenHeight:      1080,
+		ScreenWidth:       1920,
+		TimeZoneOffset:    "+60",
+		UserAgent:         "Mozilla/5.0",
+		JavascriptEnabled: true,
+	}
+	if *b != expected {
+		test.Fatalf("Invalid browser info: got %+v, should be %+v", *b, expected)
+	}
+	var body struct{ Amount int }
+	if err := json.NewDecoder(req.Body).Decode(&body); err != nil || body.Amount != 1000 {
+		test.Errorf("request body consumed: %v", err)
+	}
+}
+
+func TestIpAddressFromRequest(test *testing.T) {
+	req, err := http.NewRequest("GET", "http://test.de/pay", nil)
+	if err != nil {
+		test.Fatal(err)
+	}
+	req.RemoteAddr = "10.0.0.1:4242"
+	req.Header.Set("X-Forwarded-For", "198.51.100.9, 203.0.113.7, 10.0.0.2")
+	for _, tt := range []struct {
+		trusted []string
+		ip      string
+	}{
+		// The header is ignored unless proxies are trusted.
+		{nil, "10.0.0.1"},
+		{[]string{"192.168.0.1"}, "10.0.0.1"},
+		// The spoofed left-most entry is never returned.
+		{[]string{"10.0.0.0/8"}, "203.0.113.7"},
+		{[]string{"10.0.0.1"}, "10.0.0.2"},
+	} {
+		if ip := IpAddressFromRequest(req, tt.trusted...); ip != tt.ip {
+			test.Errorf("trusted proxies %v: expected %s, got %s", tt.trusted, tt.ip, ip)
+		}
+	}
+}
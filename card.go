@@ -7,9 +7,76 @@ package mango
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
+	"net/url"
 	"strings"
 )
 
+const (
+	CardRegistrationStatusCreated   = "CREATED"
+	CardRegistrationStatusValidated = "VALIDATED"
+	CardRegistrationStatusError     = "ERROR"
+)
+
+// Card registration failure causes, to be tested with errors.Is on an
+// ErrCardRegistrationFailed.
+var (
+	ErrCardNumberInvalid   = errors.New("invalid card number")
+	ErrCardDateInvalid     = errors.New("invalid card expiration date")
+	ErrCardExpired         = errors.New("card expired")
+	ErrCardCVVInvalid      = errors.New("invalid card CVV")
+	ErrCardInactive        = errors.New("card not active")
+	ErrCardTokenTimeout    = errors.New("card registration token timed out")
+	ErrCardTokenizerFailed = errors.New("card tokenization server error")
+)
+
+// Causes of tokenization server error codes and card registration result
+// codes.
+var cardRegistrationErrors = map[string]error{
+	"02625":  ErrCardNumberInvalid,
+	"105202": ErrCardNumberInvalid,
+	"02626":  ErrCardDateInvalid,
+	"105203": ErrCardDateInvalid,
+	"02624":  ErrCardExpired,
+	"02627":  ErrCardCVVInvalid,
+	"105204": ErrCardCVVInvalid,
+	"01902":  ErrCardInactive,
+	"02631":  ErrCardTokenTimeout,
+	"105299": ErrCardTokenizerFailed,
+	"02101":  ErrCardTokenizerFailed,
+	"09101":  ErrCardTokenizerFailed,
+	"09102":  ErrCardTokenizerFailed,
+	"09104":  ErrCardTokenizerFailed,
+	"09201":  ErrCardTokenizerFailed,
+}
+
+// ErrCardRegistrationFailed is returned when a card can't be registered,
+// either because the tokenization server returned an error code or because
+// MangoPay refused the registration data. Use errors.Is to check the
+// cause, i.e ErrCardExpired; unknown codes have a nil cause.
+type ErrCardRegistrationFailed struct {
+	ID   string
+	Code string
+	Msg  string
+	err  error
+}
+
+func (e *ErrCardRegistrationFailed) Error() string {
+	msg := e.Msg
+	if msg == "" && e.err != nil {
+		msg = e.err.Error()
+	}
+	return fmt.Sprintf("card registration %s failed: %s (code %s)", e.ID, msg, e.Code)
+}
+
+func (e *ErrCardRegistrationFailed) Unwrap() error {
+	return e.err
+}
+
+func newErrCardRegistrationFailed(id, code, msg string) *ErrCardRegistrationFailed {
+	return &ErrCardRegistrationFailed{ID: id, Code: code, Msg: msg, err: cardRegistrationErrors[code]}
+}
+
 // List of cards.
 type CardList []*Card
 
@@ -46,6 +113,19 @@ func (c *CardRegistration) String() string {
 	return struct2string(c)
 }
 
+// CardRegistration fetches a card registration. Register can be called on
+// a registration still in the CREATED status.
+func (m *MangoPay) CardRegistration(id string) (*CardRegistration, error) {
+	any, err := m.anyRequest(new(CardRegistration), actionFetchCardRegistration, JsonObject{"Id": id})
+	if err != nil {
+		return nil, err
+	}
+	c := any.(*CardRegistration)
+	c.service = m
+	c.isInitialized = c.Status == CardRegistrationStatusCreated
+	return c, nil
+}
+
 // Card holds all credit card details.
 type Card struct {
 	ProcessIdent
@@ -58,6 +138,12 @@ type Card struct {
 	Active         bool
 	Currency       string // Currency accepted in the waller, i.e EUR, USD etc.
 	Validity       string // UNKNOWN, VALID, INVALID
+	UserId         string
+	Country        string
+	// Unique identifier of the card number, the same for all registrations
+	// of a physical card.
+	Fingerprint string
+	service     *MangoPay
 }
 
 func (c *Card) String() string {
@@ -70,7 +156,9 @@ func (m *MangoPay) Card(id string) (*Card, error) {
 	if err != nil {
 		return nil, err
 	}
-	return any.(*Card), nil
+	c := any.(*Card)
+	c.service = m
+	return c, nil
 }
 
 // Card finds all user's cards.
@@ -87,9 +175,184 @@ func (m *MangoPay) Cards(user Consumer) (CardList, error) {
 	if err := m.unMarshalJSONResponse(resp, &cl); err != nil {
 		return nil, err
 	}
+	for _, c := range cl {
+		c.service = m
+	}
 	return cl, nil
 }
 
+// CardsByFingerprint finds all cards sharing the same fingerprint, i.e
+// all registrations of a physical card, whatever the user.
+func (m *MangoPay) CardsByFingerprint(fingerprint string) (CardList, error) {
+	if fingerprint == "" {
+		return nil, errors.New("empty card fingerprint")
+	}
+	list, err := m.anyRequest(new(CardList), actionFetchCardsByFingerprint,
+		JsonObject{"Fingerprint": fingerprint})
+	if err != nil {
+		return nil, err
+	}
+	casted := *(list.(*CardList))
+	for _, c := range casted {
+		c.service = m
+	}
+	return casted, nil
+}
+
+// CardTransactions finds the transactions made with a card. filter is
+// optional.
+func (m *MangoPay) CardTransactions(card *Card, filter *TransactionFilter) (TransferList, error) {
+	if card == nil || card.Id == "" {
+		return nil, errors.New("card has empty Id")
+	}
+	trs, err := m.anyQueryRequest(new(TransferList), actionFetchCardTransactions,
+		JsonObject{"Id": card.Id}, filter.values())
+	if err != nil {
+		return nil, err
+	}
+	return *(trs.(*TransferList)), nil
+}
+
+// Deactivate deactivates the card. A deactivated card can't be used for
+// payments anymore, and can't be reactivated.
+func (c *Card) Deactivate() error {
+	if c.Id == "" {
+		return errors.New("card has empty Id")
+	}
+	if c.service == nil {
+		return errors.New("card not fetched from MangoPay")
+	}
+	any, err := c.service.anyRequest(new(Card), actionEditCard,
+		JsonObject{"Id": c.Id, "Active": false})
+	if err != nil {
+		return err
+	}
+	serv := c.service
+	*c = *(any.(*Card))
+	c.service = serv
+	return nil
+}
+
+// ErrCardValidationFailed is returned when a card validation has failed.
+type ErrCardValidationFailed struct {
+	ID   string
+	Msg  string
+	Code string
+}
+
+func (e *ErrCardValidationFailed) Error() string {
+	return fmt.Sprintf("card validation %s failed: %s ", e.ID, e.Msg)
+}
+
+// CardValidation checks that a card is valid without charging it, i.e
+// before saving it for future merchant-initiated payments. Once
+// succeeded, the card's Validity is VALID.
+//
+// See https://docs.mangopay.com/endpoints/v2.01/cards#e1042_validate-a-card
+type CardValidation struct {
+	ProcessReply
+	AuthorId              string
+	CardId                string
+	Type                  string
+	Validity              string // UNKNOWN, VALID or INVALID
+	SecureMode            string
+	SecureModeNeeded      bool
+	SecureModeReturnURL   string
+	SecureModeRedirectURL string
+	BrowserInfo           *BrowserInfo `json:",omitempty"`
+	IpAddress             string       `json:",omitempty"`
+	service               *MangoPay
+}
+
+func (v *CardValidation) String() string {
+	return struct2string(v)
+}
+
+// NewCardValidation creates a validation of a registered card. BrowserInfo
+// and ipAddress are required by 3DS2: the user may have to authenticate
+// at SecureModeRedirectURL, then is sent back to returnURL.
+func (m *MangoPay) NewCardValidation(author Consumer, card *Card, browser *BrowserInfo, ipAddress, returnURL string) (*CardValidation, error) {
+	msg := "new card validation: "
+	id := consumerId(author)
+	if id == "" {
+		return nil, errors.New(msg + "author has empty Id")
+	}
+	if card == nil || card.Id == "" {
+		return nil, errors.New(msg + "card has empty Id")
+	}
+	if browser == nil {
+		return nil, errors.New(msg + "nil browser info")
+	}
+	if ipAddress == "" {
+		return nil, errors.New(msg + "empty IP address")
+	}
+	if returnURL == "" {
+		return nil, errors.New(msg + "empty return url")
+	}
+	u, err := url.Parse(returnURL)
+	if err != nil {
+		return nil, errors.New(msg + err.Error())
+	}
+	v := &CardValidation{
+		AuthorId:            id,
+		CardId:              card.Id,
+		BrowserInfo:         browser,
+		IpAddress:           ipAddress,
+		SecureModeReturnURL: u.String(),
+		service:             m,
+	}
+	return v, nil
+}
+
+// Save sends an HTTP query to create the card validation. It returns an
+// ErrCardValidationFailed error if the validation has failed.
+func (v *CardValidation) Save() error {
+	data := JsonObject{}
+	j, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(j, &data); err != nil {
+		return err
+	}
+
+	// Fields not allowed when creating a card validation.
+	for _, field := range []string{"Id", "CreationDate", "ExecutionDate", "ResultCode",
+		"ResultMessage", "Status", "Type", "Validity", "SecureMode", "SecureModeNeeded",
+		"SecureModeRedirectURL"} {
+
+		delete(data, field)
+	}
+
+	ins, err := v.service.anyRequest(new(CardValidation), actionCreateCardValidation, data)
+	if err != nil {
+		return err
+	}
+	serv := v.service
+	*v = *(ins.(*CardValidation))
+	v.service = serv
+
+	if v.Status == "FAILED" {
+		return &ErrCardValidationFailed{v.Id, v.ResultMessage, v.ResultCode}
+	}
+	return nil
+}
+
+// CardValidation fetches a past validation of a card.
+func (m *MangoPay) CardValidation(card *Card, id string) (*CardValidation, error) {
+	if card == nil || card.Id == "" {
+		return nil, errors.New("card has empty Id")
+	}
+	any, err := m.anyRequest(new(CardValidation), actionFetchCardValidation,
+		JsonObject{"CardId": card.Id, "Id": id})
+	if err != nil {
+		return nil, err
+	}
+	v := any.(*CardValidation)
+	v.service = m
+	return v, nil
+}
+
 // NewCardRegistration creates a new credit card registration object that can
 // be used to register a new credit card for a given user.
 //
@@ -198,10 +461,35 @@ func (c *CardRegistration) Init() error {
 // registrationData value is returned by the external banking service that deals with
 // the credit card information, and is obtained by submitting an HTML form to
 // the external banking service.
+//
+// If the external banking service returned an error code (i.e
+// "errorCode=02625"), it is still sent to MangoPay as "data=errorCode=02625"
+// so that the registration records the failure, then an
+// *ErrCardRegistrationFailed is returned. The same error is returned if
+// MangoPay refuses the registration data.
 func (c *CardRegistration) Register(registrationData string) error {
+	if strings.HasPrefix(registrationData, "errorCode=") {
+		code := strings.TrimSpace(strings.TrimPrefix(registrationData, "errorCode="))
+		if err := c.sendData("data=" + registrationData); err != nil {
+			return newErrCardRegistrationFailed(c.Id, code,
+				"unable to send registration data: "+err.Error())
+		}
+		return newErrCardRegistrationFailed(c.Id, code, c.ResultMessage)
+	}
 	if !strings.HasPrefix(registrationData, "data=") {
 		return errors.New("invalid registration data. Must start with data=")
 	}
+	if err := c.sendData(registrationData); err != nil {
+		return err
+	}
+	if c.Status == CardRegistrationStatusError {
+		return newErrCardRegistrationFailed(c.Id, c.ResultCode, c.ResultMessage)
+	}
+	return nil
+}
+
+// sendData sends the registration data to MangoPay and updates c.
+func (c *CardRegistration) sendData(registrationData string) error {
 	if !c.isInitialized {
 		return errors.New("card registration process not initialized. Did you call Init() first?")
 	}
@@ -0,0 +1,65 @@
+package mango
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestCardRegistrationErrorCode(test *testing.T) {
+	var sent string
+	m := newFakeService(test, func(w http.ResponseWriter, req *http.Request) {
+		var body struct{ RegistrationData string }
+		if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+			test.Error(err)
+		}
+		sent = body.RegistrationData
+		w.Write([]byte(`{"Id": "1", "Status": "ERROR", "ResultMessage": "refused"}`))
+	})
+	c := &CardRegistration{isInitialized: true, service: m}
+	c.Id = "1"
+	for code, cause := range map[string]error{
+		"02625": ErrCardNumberInvalid,
+		"02624": ErrCardExpired,
+		"02627": ErrCardCVVInvalid,
+		"02631": ErrCardTokenTimeout,
+		"99999": nil,
+	} {
+		err := c.Register("errorCode=" + code)
+		var failed *ErrCardRegistrationFailed
+		if !errors.As(err, &failed) {
+			test.Fatalf("code %s: expected *ErrCardRegistrationFailed, got %v", code, err)
+		}
+		if failed.Code != code || failed.Unwrap() != cause {
+			test.Errorf("code %s: unexpected error %v", code, err)
+		}
+		if cause != nil && !errors.Is(err, cause) {
+			test.Errorf("code %s: expected error to wrap %v", code, cause)
+		}
+		if sent != "data=errorCode="+code || c.Status != CardRegistrationStatusError {
+			test.Errorf("code %s: error not sent to MangoPay (sent %q)", code, sent)
+		}
+	}
+}
+
+func TestNewCardValidation(test *testing.T) {
+	m := new(MangoPay)
+	user := m.UserRef("1")
+	card := &Card{}
+	card.Id = "2"
+	browser := &BrowserInfo{}
+	if _, err := m.NewCardValidation(user, card, nil, "1.2.3.4", "http://return"); err == nil {
+		test.Error("expected an error for missing browser info")
+	}
+	if _, err := m.NewCardValidation(user, card, browser, "", "http://return"); err == nil {
+		test.Error("expected an error for missing IP address")
+	}
+	v, err := m.NewCardValidation(user, card, browser, "1.2.3.4", "http://return")
+	if err != nil {
+		test.Fatal(err)
+	}
+	if v.AuthorId != "1" || v.CardId != "2" {
+		test.Errorf("unexpected card validation: %v", v)
+	}
+}
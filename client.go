@@ -0,0 +1,154 @@
+// Copyright 2014 Mathias Monnerville. All rights reserved.
+// Use of this source code is governed by a GPL
+// license that can be found in the LICENSE file.
+
+package mango
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+)
+
+// Maximum size of a client logo, in bytes.
+const maxLogoSize = 1 << 20
+
+// Colours must be in hexadecimal format, i.e #FF0000.
+var colourRegexp = regexp.MustCompile(`^#[0-9A-Fa-f]{6}$`)
+
+// PlatformCategorization describes the business of the platform.
+type PlatformCategorization struct {
+	BusinessType string
+	Sector       string
+}
+
+// Client holds the details of the platform registered at MangoPay.
+//
+// See https://docs.mangopay.com/endpoints/v2.01/clients
+type Client struct {
+	ClientId                string
+	Name                    string
+	RegisteredName          string
+	PrimaryButtonColour     string
+	PrimaryThemeColour      string
+	TechEmails              []string
+	AdminEmails             []string
+	FraudEmails             []string
+	BillingEmails           []string
+	PlatformDescription     string
+	PlatformCategorization  *PlatformCategorization `json:",omitempty"`
+	PlatformURL             string
+	HeadquartersAddress     *Address `json:",omitempty"`
+	HeadquartersPhoneNumber string
+	TaxNumber               string
+	CompanyReference        string
+	Logo                    string // URL of the logo
+	service                 *MangoPay
+}
+
+func (c *Client) String() string {
+	return struct2string(c)
+}
+
+// Client fetches the details of the platform.
+func (m *MangoPay) Client() (*Client, error) {
+	any, err := m.anyRequest(new(Client), actionFetchClient, nil)
+	if err != nil {
+		return nil, err
+	}
+	c := any.(*Client)
+	c.service = m
+	return c, nil
+}
+
+// Save updates the platform's details. Empty values are not sent so that
+// existing ones don't get overwritten. The name, registered name, client
+// Id and company reference can't be updated. The headquarters address is
+// checked when set.
+func (c *Client) Save() error {
+	for _, colour := range []string{c.PrimaryButtonColour, c.PrimaryThemeColour} {
+		if colour != "" && !colourRegexp.MatchString(colour) {
+			return fmt.Errorf("invalid colour %q: must be #RRGGBB", colour)
+		}
+	}
+	if !c.HeadquartersAddress.IsEmpty() {
+		if err := c.HeadquartersAddress.Validate(); err != nil {
+			return err
+		}
+	}
+
+	data := JsonObject{}
+	j, err := json.Marshal(c)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(j, &data); err != nil {
+		return err
+	}
+
+	// Fields not allowed when updating a client.
+	for _, field := range []string{"ClientId", "Name", "RegisteredName", "CompanyReference", "Logo"} {
+		delete(data, field)
+	}
+	// Delete empty values so that existing ones don't get
+	// overwritten with empty values.
+	for k, v := range data {
+		switch casted := v.(type) {
+		case string:
+			if casted == "" {
+				delete(data, k)
+			}
+		case []interface{}:
+			if len(casted) == 0 {
+				delete(data, k)
+			}
+		case nil:
+			delete(data, k)
+		}
+	}
+
+	ins, err := c.service.anyRequest(new(Client), actionEditClient, data)
+	if err != nil {
+		return err
+	}
+	serv := c.service
+	*c = *(ins.(*Client))
+	c.service = serv
+	return nil
+}
+
+// UploadLogo uploads the platform's logo. The image must be a PNG, JPEG or
+// GIF file of at most 1 MB.
+func (m *MangoPay) UploadLogo(r io.Reader) error {
+	if r == nil {
+		return errors.New("upload logo: nil reader")
+	}
+	var buf bytes.Buffer
+	// Read one more byte than allowed to detect oversized files.
+	n, err := io.Copy(&buf, io.LimitReader(r, maxLogoSize+1))
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return errors.New("upload logo: empty file")
+	}
+	if n > maxLogoSize {
+		return fmt.Errorf("upload logo: file is larger than %d bytes", maxLogoSize)
+	}
+	switch ct := http.DetectContentType(buf.Bytes()); ct {
+	case "image/png", "image/jpeg", "image/gif":
+	default:
+		return fmt.Errorf("upload logo: unsupported file format %s", ct)
+	}
+
+	data := JsonObject{
+		"File": base64.StdEncoding.EncodeToString(buf.Bytes()),
+	}
+	_, err = m.anyRequest(new(JsonObject), actionUploadClientLogo, data)
+	return err
+}
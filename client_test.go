@@ -0,0 +1,37 @@
+package mango
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestUploadLogo_Invalid(test *testing.T) {
+	serv := &MangoPay{}
+	cases := []struct {
+		name   string
+		file   []byte
+		errMsg string
+	}{
+		{"empty", []byte{}, "empty file"},
+		{"text", []byte("not an image"), "unsupported file format"},
+		{"too large", append(newPngImageFile(), make([]byte, maxLogoSize)...), "larger than"},
+	}
+	for _, c := range cases {
+		err := serv.UploadLogo(bytes.NewReader(c.file))
+		if err == nil || !strings.Contains(err.Error(), c.errMsg) {
+			test.Fatalf("%s: expected error containing %q, got %v", c.name, c.errMsg, err)
+		}
+	}
+}
+
+func TestClient_SaveInvalidColour(test *testing.T) {
+	c := &Client{PrimaryButtonColour: "red"}
+	if err := c.Save(); err == nil || !strings.Contains(err.Error(), "invalid colour") {
+		test.Fatalf("Expected invalid colour error, got %v", err)
+	}
+	c = &Client{HeadquartersAddress: &Address{AddressLine1: "1 rue de la Paix", Country: "FR"}}
+	if err := c.Save(); err == nil || !strings.Contains(err.Error(), "invalid address") {
+		test.Fatalf("Expected invalid address error, got %v", err)
+	}
+}
@@ -177,6 +177,10 @@ Options:
 		}
 		n := service.NewNaturalUser(u.FirstName, u.LastName, u.Email, u.UserCategory, u.Birthday, u.Nationality,
 			u.CountryOfResidence, u.TermsAndConditionsAccepted)
+		// Required for OWNER users.
+		n.Address = u.Address
+		n.Occupation = u.Occupation
+		n.IncomeRange = u.IncomeRange
 		if err := n.Save(); err != nil {
 			perror(err.Error())
 		}
@@ -189,6 +193,10 @@ Options:
 		}
 		n := service.NewNaturalUser(u.FirstName, u.LastName, u.Email, u.UserCategory, u.Birthday, u.Nationality,
 			u.CountryOfResidence, u.TermsAndConditionsAccepted)
+		// Required for OWNER users.
+		n.Address = u.Address
+		n.Occupation = u.Occupation
+		n.IncomeRange = u.IncomeRange
 		if err := n.Save(); err != nil {
 			perror(err.Error())
 		}
@@ -272,9 +280,7 @@ Options:
 		}
 		ows := mango.ConsumerList{}
 		for _, o := range w.Owners {
-			u := new(mango.LegalUser)
-			u.User = mango.User{ProcessIdent: mango.ProcessIdent{Id: o}}
-			ows = append(ows, u)
+			ows = append(ows, service.UserRef(o))
 		}
 		n, err := service.NewWallet(ows, w.Description, w.Currency)
 		if err != nil {
@@ -304,9 +310,7 @@ Options:
 		}
 		ows := mango.ConsumerList{}
 		for _, o := range w.Owners {
-			u := new(mango.LegalUser)
-			u.User = mango.User{ProcessIdent: mango.ProcessIdent{Id: o}}
-			ows = append(ows, u)
+			ows = append(ows, service.UserRef(o))
 		}
 		n, err := service.NewWallet(ows, w.Description, w.Currency)
 		if err != nil {
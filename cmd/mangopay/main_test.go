@@ -38,7 +38,7 @@ type user struct {
 	email, country string
 	birthday       int64
 	ccn, cvv, exp  string // Credit card number, CVV, exp. date (MMYY)
-	category       string
+	category       mango.UserCategory
 	nationality    string
 	terms          bool
 	wallet         *mango.Wallet
@@ -94,9 +94,9 @@ func init() {
 
 	usersinfo = []user{
 		{firstName1, lastName1, email1, country, birth1,
-			"4970101122334463", "123", "0219", "", "French", true, nil, nil},
+			"4970101122334463", "123", "0219", mango.UserCategoryPayer, "French", true, nil, nil},
 		{firstName2, lastName2, email2, country, birth2,
-			"4970101122334471", "123", "0919", "", "English", true, nil, nil},
+			"4970101122334471", "123", "0919", mango.UserCategoryPayer, "English", true, nil, nil},
 	}
 	users = make([]*mango.NaturalUser, 2)
 }
@@ -262,7 +262,12 @@ func TestCreateBankAccounts(t *testing.T) {
 	}
 	for k, u := range users {
 		log.Printf("Creating IBAN account for %s ...", u.FirstName)
-		acc, err := service.NewBankAccount(u, u.FirstName, "one great place", mango.IBAN)
+		acc, err := service.NewBankAccount(u, u.FirstName, &mango.Address{
+			AddressLine1: "1 rue de la Paix",
+			City:         "Paris",
+			PostalCode:   "75002",
+			Country:      "FR",
+		}, mango.IBAN)
 		if err != nil {
 			t.Fatal(err.Error())
 		}
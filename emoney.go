@@ -0,0 +1,74 @@
+// Copyright 2014 Mathias Monnerville. All rights reserved.
+// Use of this source code is governed by a GPL
+// license that can be found in the LICENSE file.
+
+package mango
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+)
+
+// UserEMoney holds the amount of e-money credited to and debited from all
+// user's wallets, over the lifetime of the user or a given period.
+//
+// See https://docs.mangopay.com/endpoints/v2.01/user-emoney
+type UserEMoney struct {
+	UserId         string
+	CreditedEMoney Money
+	DebitedEMoney  Money
+}
+
+func (e *UserEMoney) String() string {
+	return struct2string(e)
+}
+
+// EMoneyPeriod restricts e-money to a given year or month.
+type EMoneyPeriod struct {
+	Year  int
+	Month int // 1 to 12, 0 for the whole year
+}
+
+func (p *EMoneyPeriod) validate() error {
+	if p.Year < 1000 || p.Year > 9999 {
+		return fmt.Errorf("invalid year %d", p.Year)
+	}
+	if p.Month < 0 || p.Month > 12 {
+		return fmt.Errorf("invalid month %d", p.Month)
+	}
+	return nil
+}
+
+// UserEMoney fetches the e-money of a user. A nil period returns the
+// amounts since the user's creation. Amounts are converted to currency,
+// or to the client's default currency if currency is empty.
+func (m *MangoPay) UserEMoney(user Consumer, period *EMoneyPeriod, currency string) (*UserEMoney, error) {
+	msg := "user emoney: "
+	id := consumerId(user)
+	if id == "" {
+		return nil, errors.New(msg + "user has empty Id")
+	}
+	action := actionFetchUserEMoney
+	data := JsonObject{"Id": id}
+	if period != nil {
+		if err := period.validate(); err != nil {
+			return nil, errors.New(msg + err.Error())
+		}
+		data["Year"] = fmt.Sprintf("%04d", period.Year)
+		action = actionFetchUserEMoneyYear
+		if period.Month != 0 {
+			data["Month"] = fmt.Sprintf("%02d", period.Month)
+			action = actionFetchUserEMoneyMonth
+		}
+	}
+	var query url.Values
+	if currency != "" {
+		query = url.Values{"currency": {currency}}
+	}
+	e, err := m.anyQueryRequest(new(UserEMoney), action, data, query)
+	if err != nil {
+		return nil, err
+	}
+	return e.(*UserEMoney), nil
+}
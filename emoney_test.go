@@ -0,0 +1,19 @@
+package mango
+
+import "testing"
+
+func TestEMoneyPeriodValidate(test *testing.T) {
+	for _, tt := range []struct {
+		period EMoneyPeriod
+		valid  bool
+	}{
+		{EMoneyPeriod{2019, 0}, true},
+		{EMoneyPeriod{2019, 12}, true},
+		{EMoneyPeriod{2019, 13}, false},
+		{EMoneyPeriod{19, 1}, false},
+	} {
+		if err := tt.period.validate(); (err == nil) != tt.valid {
+			test.Errorf("period %v: expected valid=%v, got %v", tt.period, tt.valid, err)
+		}
+	}
+}
@@ -80,9 +80,13 @@ const (
 	EventTransferSettlementSucceeded              = "TRANSFER_SETTLEMENT_SUCCEEDED"
 	EventTransferSettlementFailed                 = "TRANSFER_SETTLEMENT_FAILED"
 	EventMandateCreated                           = "MANDATE_CREATED"
-	EventMandatedFailed                           = "MANDATED_FAILED"
+	EventMandateFailed                            = "MANDATE_FAILED"
 	EventMandateActivated                         = "MANDATE_ACTIVATED"
 	EventMandateSubmitted                         = "MANDATE_SUBMITTED"
+	EventMandateExpired                           = "MANDATE_EXPIRED"
+
+	// Deprecated: MangoPay sends EventMandateFailed.
+	EventMandatedFailed = "MANDATED_FAILED"
 )
 
 // Events returns a list of all financial events. This include PayIns, PayOuts and
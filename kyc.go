@@ -1,10 +1,36 @@
 package mango
 
 import (
+	"bufio"
 	"encoding/base64"
 	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
 )
 
+// Size bounds of a KYC page file, in bytes.
+const (
+	minPageSize = 1 << 10
+	maxPageSize = 10 << 20
+)
+
+// Content types accepted for KYC pages, with their file extensions.
+var pageContentTypes = map[string][]string{
+	"application/pdf": {".pdf"},
+	"image/jpeg":      {".jpg", ".jpeg"},
+	"image/gif":       {".gif"},
+	"image/png":       {".png"},
+}
+
+// PDFSplitFunc splits a PDF file into single page PDF files. The SDK
+// doesn't embed a PDF library: provide one to CreatePagesFrom to upload
+// each page of a PDF separately.
+type PDFSplitFunc func(r io.Reader) ([]io.Reader, error)
+
 type DocumentType string
 
 const (
@@ -38,6 +64,29 @@ const (
 	DocumentRefusedReasonTypeSpecificCase        DocumentRefusedReasonType = "SPECIFIC_CASE"
 )
 
+// RequiredDocuments returns the documents a user must have validated to
+// reach the REGULAR KYC level. Legal users' requirements depend on their
+// LegalPersonType. Only OWNER users can reach that level, so the documents
+// returned are those of an OWNER whatever the user's category. ADDRESS_PROOF
+// is never required but may still be uploaded.
+func RequiredDocuments(user Person) []DocumentType {
+	switch u := user.(type) {
+	case *NaturalUser:
+		return []DocumentType{IdentityProof}
+	case *LegalUser:
+		switch u.LegalPersonType {
+		case LegalPersonTypeSoletrader:
+			return []DocumentType{IdentityProof, RegistrationProof}
+		case LegalPersonTypeOrganization:
+			return []DocumentType{IdentityProof, RegistrationProof, ArticlesOfAssociation}
+		default:
+			return []DocumentType{IdentityProof, RegistrationProof, ArticlesOfAssociation,
+				ShareholderDeclaration}
+		}
+	}
+	return nil
+}
+
 func (m *MangoPay) Document(id string) (*Document, error) {
 	any, err := m.anyRequest(new(Document), actionFetchKYCDocument, JsonObject{"Id": id})
 	if err != nil {
@@ -134,3 +183,163 @@ func (d *Document) CreatePage(file []byte) error {
 	_, err := d.service.anyRequest(new(JsonObject), actionCreateKYCPage, data)
 	return err
 }
+
+// CreatePageFrom uploads a page read from r. The file must be a PDF, JPEG,
+// GIF or PNG file between 1 KB and 10 MB. Its content type is checked
+// before sending anything, as well as its size when r is an *os.File or
+// has a Len or Size method. Otherwise, the upload is aborted mid-request as
+// soon as more than 10 MB are read: the body is cut short, so MangoPay never
+// receives a valid page. filename is optional; if set, its extension must
+// match the content type.
+//
+// The file is base64-encoded while being sent, so it is never held in
+// memory.
+func (d *Document) CreatePageFrom(r io.Reader, filename string) error {
+	msg := "create page: "
+	if r == nil {
+		return errors.New(msg + "nil reader")
+	}
+	if size, ok := readerSize(r); ok {
+		if err := checkPageSize(size); err != nil {
+			return errors.New(msg + err.Error())
+		}
+	}
+	br := bufio.NewReaderSize(r, minPageSize)
+	head, err := br.Peek(minPageSize)
+	if err != nil && err != io.EOF {
+		return err
+	}
+	if err := checkPageSize(int64(len(head))); err != nil {
+		return errors.New(msg + err.Error())
+	}
+	if _, err := pageContentType(head, filename); err != nil {
+		return errors.New(msg + err.Error())
+	}
+
+	method, uri, err := d.service.actionURI(actionCreateKYCPage,
+		JsonObject{"UserId": d.UserId, "Id": d.Id}, nil)
+	if err != nil {
+		return err
+	}
+	pr, pw := io.Pipe()
+	encErr := make(chan error, 1)
+	go func() {
+		err := encodePage(pw, br)
+		encErr <- err
+		pw.CloseWithError(err)
+	}()
+	resp, err := d.service.streamRequest(method, "application/json", uri, pr,
+		fmt.Sprintf(`{"File": %q}`, "<"+filename+">"), true)
+	// Unblocks the encoder if the request ended before reading the body.
+	pr.Close()
+	if e := <-encErr; e != nil && e != io.ErrClosedPipe {
+		if resp != nil {
+			resp.Body.Close()
+		}
+		return errors.New(msg + e.Error())
+	}
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// CreatePagesFrom uploads the file read from r like CreatePageFrom. If the
+// file is a PDF and split is not nil, each of its pages is uploaded as a
+// separate page; otherwise the file is uploaded as a single page.
+func (d *Document) CreatePagesFrom(r io.Reader, filename string, split PDFSplitFunc) error {
+	if r == nil {
+		return errors.New("create pages: nil reader")
+	}
+	br := bufio.NewReaderSize(r, minPageSize)
+	head, err := br.Peek(minPageSize)
+	if err != nil && err != io.EOF {
+		return err
+	}
+	if split == nil || http.DetectContentType(head) != "application/pdf" {
+		return d.CreatePageFrom(br, filename)
+	}
+	pages, err := split(br)
+	if err != nil {
+		return err
+	}
+	for k, page := range pages {
+		if err := d.CreatePageFrom(page, filename); err != nil {
+			return fmt.Errorf("page %d: %s", k+1, err.Error())
+		}
+	}
+	return nil
+}
+
+// encodePage writes the JSON body of a page upload to w, base64-encoding
+// the file read from r.
+func encodePage(w io.Writer, r io.Reader) error {
+	if _, err := io.WriteString(w, `{"File":"`); err != nil {
+		return err
+	}
+	enc := base64.NewEncoder(base64.StdEncoding, w)
+	n, err := io.Copy(enc, io.LimitReader(r, maxPageSize+1))
+	if err != nil {
+		return err
+	}
+	if n > maxPageSize {
+		return checkPageSize(n)
+	}
+	if err := enc.Close(); err != nil {
+		return err
+	}
+	_, err = io.WriteString(w, `"}`)
+	return err
+}
+
+// readerSize returns the size of the data held by r, if known.
+func readerSize(r io.Reader) (int64, bool) {
+	switch v := r.(type) {
+	case *os.File:
+		fi, err := v.Stat()
+		if err != nil || !fi.Mode().IsRegular() {
+			return 0, false
+		}
+		pos, err := v.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return 0, false
+		}
+		return fi.Size() - pos, true
+	case interface{ Len() int }:
+		return int64(v.Len()), true
+	case interface{ Size() int64 }:
+		return v.Size(), true
+	}
+	return 0, false
+}
+
+func checkPageSize(size int64) error {
+	if size < minPageSize {
+		return fmt.Errorf("file is smaller than %d bytes", minPageSize)
+	}
+	if size > maxPageSize {
+		return fmt.Errorf("file is larger than %d bytes", maxPageSize)
+	}
+	return nil
+}
+
+// pageContentType sniffs the content type of a page from its first bytes
+// and checks it against the extension of filename, if any.
+func pageContentType(head []byte, filename string) (string, error) {
+	ct := http.DetectContentType(head)
+	exts, ok := pageContentTypes[ct]
+	if !ok {
+		return "", fmt.Errorf("unsupported file format %s", ct)
+	}
+	ext := strings.ToLower(filepath.Ext(filename))
+	if ext == "" {
+		return ct, nil
+	}
+	for _, e := range exts {
+		if e == ext {
+			return ct, nil
+		}
+	}
+	return "", fmt.Errorf("file extension %s does not match content type %s", ext, ct)
+}
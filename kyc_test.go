@@ -2,9 +2,16 @@ package mango
 
 import (
 	"bytes"
+	"encoding/base64"
+	"encoding/json"
 	"image"
 	"image/png"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestKYC(test *testing.T) {
@@ -34,3 +41,70 @@ func newPngImageFile() []byte {
 	}
 	return buffer.Bytes()
 }
+
+func TestCreatePageFromChecks(test *testing.T) {
+	doc := new(Document)
+	page := newPngImageFile()
+	for _, tt := range []struct {
+		data     []byte
+		filename string
+	}{
+		{page[:100], "id.png"},
+		{bytes.Repeat([]byte("text"), 1000), "id.txt"},
+		{page, "id.pdf"},
+		{make([]byte, maxPageSize+1), ""},
+	} {
+		if err := doc.CreatePageFrom(bytes.NewReader(tt.data), tt.filename); err == nil {
+			test.Errorf("expected an error for %q (%d bytes)", tt.filename, len(tt.data))
+		}
+	}
+	if ct, err := pageContentType(page, "ID.PNG"); err != nil || ct != "image/png" {
+		test.Errorf("expected image/png, got %q (%v)", ct, err)
+	}
+}
+
+func TestEncodePage(test *testing.T) {
+	page := newPngImageFile()
+	var buf bytes.Buffer
+	if err := encodePage(&buf, bytes.NewReader(page)); err != nil {
+		test.Fatal(err)
+	}
+	var body struct{ File string }
+	if err := json.Unmarshal(buf.Bytes(), &body); err != nil {
+		test.Fatal(err)
+	}
+	if body.File != base64.StdEncoding.EncodeToString(page) {
+		test.Error("page not properly encoded")
+	}
+	err := encodePage(&buf, strings.NewReader(strings.Repeat("a", maxPageSize+1)))
+	if err == nil {
+		test.Error("expected an error for an oversized page")
+	}
+}
+
+func TestCreatePageFromOversizedStream(test *testing.T) {
+	valid := make(chan bool, 1)
+	m := newFakeService(test, func(w http.ResponseWriter, req *http.Request) {
+		body, err := ioutil.ReadAll(req.Body)
+		var page struct{ File string }
+		valid <- err == nil && json.Unmarshal(body, &page) == nil
+		w.Write([]byte(`{}`))
+	})
+	doc := &Document{UserId: "1", service: m}
+	doc.Id = "d1"
+	// The size of a plain reader is unknown until it is read.
+	page := newPngImageFile()
+	r := io.MultiReader(bytes.NewReader(page), bytes.NewReader(make([]byte, maxPageSize)))
+	err := doc.CreatePageFrom(struct{ io.Reader }{r}, "id.png")
+	if err == nil || !strings.Contains(err.Error(), "larger than") {
+		test.Errorf("expected an error for an oversized page, got %v", err)
+	}
+	select {
+	case ok := <-valid:
+		if ok {
+			test.Error("server received a valid page")
+		}
+	case <-time.After(5 * time.Second):
+		// The request was aborted before reaching the handler.
+	}
+}
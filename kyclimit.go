@@ -0,0 +1,212 @@
+// Copyright 2014 Mathias Monnerville. All rights reserved.
+// Use of this source code is governed by a GPL
+// license that can be found in the LICENSE file.
+
+package mango
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+)
+
+// KYCLimits are the maximum cumulative amounts of e-money a user at the
+// LIGHT KYC level can receive and withdraw. They depend on the platform's
+// contract with MangoPay.
+type KYCLimits struct {
+	Inflows  Money // Credited e-money
+	Outflows Money // Debited e-money
+}
+
+// DefaultKYCLimits are MangoPay's standard limits for LIGHT users.
+var DefaultKYCLimits = KYCLimits{
+	Inflows:  Money{Currency: "EUR", Amount: 250000},
+	Outflows: Money{Currency: "EUR", Amount: 100000},
+}
+
+// KYCCheckReason explains why a KYC check failed.
+type KYCCheckReason string
+
+const (
+	KYCCheckReasonNone          KYCCheckReason = ""
+	KYCCheckReasonUserBlocked   KYCCheckReason = "USER_BLOCKED"
+	KYCCheckReasonInflowsLimit  KYCCheckReason = "INFLOWS_LIMIT_REACHED"
+	KYCCheckReasonOutflowsLimit KYCCheckReason = "OUTFLOWS_LIMIT_REACHED"
+)
+
+// KYCCheck is the result of a pre-flight KYC check.
+type KYCCheck struct {
+	Allowed  bool
+	Reason   KYCCheckReason
+	KYCLevel string
+	// Amount the user can still receive or withdraw. Only meaningful for
+	// LIGHT users.
+	Remaining Money
+	// ActionCode given by MangoPay when the user is blocked.
+	ActionCode string
+	// Documents to validate for the user to reach the REGULAR level, set
+	// when a limit is reached.
+	MissingDocuments []DocumentType
+	// Ids of pending transactions left out of Remaining because their
+	// currency differs from the limit's.
+	SkippedTransactions []string
+}
+
+func (c *KYCCheck) String() string {
+	return struct2string(c)
+}
+
+// KYCLimitTracker checks whether a user can receive or withdraw money
+// before creating a payin or a payout. It relies on the user's KYC level,
+// regulatory status, e-money and pending transactions.
+type KYCLimitTracker struct {
+	Limits  KYCLimits
+	service *MangoPay
+}
+
+// NewKYCLimitTracker returns a tracker using limits.
+func (m *MangoPay) NewKYCLimitTracker(limits KYCLimits) *KYCLimitTracker {
+	return &KYCLimitTracker{Limits: limits, service: m}
+}
+
+// CanReceive checks whether user can be credited amount now, i.e before
+// calling NewWebPayIn.
+func (t *KYCLimitTracker) CanReceive(user Person, amount Money) (*KYCCheck, error) {
+	return t.check(user, amount, true)
+}
+
+// CanWithdraw checks whether user can be debited amount now, i.e before
+// calling NewPayOut.
+func (t *KYCLimitTracker) CanWithdraw(user Person, amount Money) (*KYCCheck, error) {
+	return t.check(user, amount, false)
+}
+
+func (t *KYCLimitTracker) check(user Person, amount Money, inflow bool) (*KYCCheck, error) {
+	msg := "kyc check: "
+	if user == nil || user.GetId() == "" {
+		return nil, errors.New(msg + "user has empty Id")
+	}
+	limit := t.Limits.Outflows
+	if inflow {
+		limit = t.Limits.Inflows
+	}
+	if amount.Currency != limit.Currency {
+		return nil, fmt.Errorf("%scurrency %s does not match limit currency %s",
+			msg, amount.Currency, limit.Currency)
+	}
+
+	check := &KYCCheck{Allowed: true, KYCLevel: user.GetKYCLevel()}
+	reg, err := t.service.UserRegulatory(user.GetId())
+	if err != nil {
+		return nil, err
+	}
+	if (inflow && reg.ScopeBlocked.Inflows) || (!inflow && reg.ScopeBlocked.Outflows) {
+		check.Allowed = false
+		check.Reason = KYCCheckReasonUserBlocked
+		check.ActionCode = reg.ActionCode
+		return check, nil
+	}
+	if check.KYCLevel == KYCLevelRegular {
+		return check, nil
+	}
+
+	used, skipped, err := t.used(user, limit.Currency, inflow)
+	if err != nil {
+		return nil, err
+	}
+	check.SkippedTransactions = skipped
+	check.Remaining = Money{Currency: limit.Currency, Amount: limit.Amount - used}
+	if check.Remaining.Amount < 0 {
+		check.Remaining.Amount = 0
+	}
+	if amount.Amount <= check.Remaining.Amount {
+		return check, nil
+	}
+
+	check.Allowed = false
+	check.Reason = KYCCheckReasonOutflowsLimit
+	if inflow {
+		check.Reason = KYCCheckReasonInflowsLimit
+	}
+	check.MissingDocuments, err = t.missingDocuments(user)
+	if err != nil {
+		return nil, err
+	}
+	return check, nil
+}
+
+// used returns the amount of e-money already credited (inflow) or debited
+// by the user, including pending transactions. Pending transactions in
+// another currency than the limit's are not counted; their ids are
+// returned instead.
+func (t *KYCLimitTracker) used(user Person, currency string, inflow bool) (int, []string, error) {
+	emoney, err := t.service.UserEMoney(user, nil, currency)
+	if err != nil {
+		return 0, nil, err
+	}
+	used := emoney.DebitedEMoney.Amount
+	txType := "PAYOUT"
+	if inflow {
+		used = emoney.CreditedEMoney.Amount
+		txType = "PAYIN"
+	}
+	skipped := []string{}
+	filter := &TransactionFilter{Status: "CREATED", Type: txType, PerPage: 100}
+	for filter.Page = 1; ; filter.Page++ {
+		pending, err := t.service.UserTransactions(user, filter)
+		if err != nil {
+			return 0, nil, err
+		}
+		for _, tr := range pending {
+			if tr.DebitedFunds.Currency != currency {
+				skipped = append(skipped, tr.Id)
+				continue
+			}
+			used += tr.DebitedFunds.Amount
+		}
+		if len(pending) < filter.PerPage {
+			break
+		}
+	}
+	return used, skipped, nil
+}
+
+// missingDocuments returns the required documents not validated yet. All
+// pages of the user's documents are read.
+func (t *KYCLimitTracker) missingDocuments(user Person) ([]DocumentType, error) {
+	const perPage = 100
+	docs := DocumentList{}
+	for page := 1; ; page++ {
+		list, err := t.service.anyQueryRequest(new(DocumentList), actionFetchUserKYCDocuments,
+			JsonObject{"UserId": user.GetId()},
+			url.Values{"page": {strconv.Itoa(page)}, "per_page": {strconv.Itoa(perPage)}})
+		if err != nil {
+			return nil, err
+		}
+		casted := *(list.(*DocumentList))
+		docs = append(docs, casted...)
+		if len(casted) < perPage {
+			break
+		}
+	}
+	return missingDocuments(RequiredDocuments(user), docs), nil
+}
+
+// missingDocuments returns the required documents types having no
+// validated document.
+func missingDocuments(required []DocumentType, docs DocumentList) []DocumentType {
+	validated := make(map[DocumentType]bool)
+	for _, d := range docs {
+		if d.Status == DocumentStatusValidated {
+			validated[d.Type] = true
+		}
+	}
+	missing := []DocumentType{}
+	for _, r := range required {
+		if !validated[r] {
+			missing = append(missing, r)
+		}
+	}
+	return missing
+}
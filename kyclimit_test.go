@@ -0,0 +1,96 @@
+package mango
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestMissingDocuments(test *testing.T) {
+	u := &LegalUser{LegalPersonType: LegalPersonTypeSoletrader}
+	docs := DocumentList{
+		{Type: IdentityProof, Status: DocumentStatusValidated},
+		{Type: RegistrationProof, Status: DocumentStatusRefused},
+	}
+	missing := missingDocuments(RequiredDocuments(u), docs)
+	if len(missing) != 1 || missing[0] != RegistrationProof {
+		test.Errorf("expected only %s to be missing, got %v", RegistrationProof, missing)
+	}
+}
+
+func TestKYCCheckCurrency(test *testing.T) {
+	t := new(MangoPay).NewKYCLimitTracker(DefaultKYCLimits)
+	u := new(MangoPay).UserRef("1")
+	if _, err := t.CanReceive(&NaturalUser{User: u.User}, Money{"GBP", 100}); err == nil {
+		test.Error("expected an error for a currency mismatch")
+	}
+}
+
+func TestKYCLimitUsedPages(test *testing.T) {
+	m := newFakeService(test, func(w http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/v2/test/users/1/emoney":
+			w.Write([]byte(`{"UserId": "1", "CreditedEMoney": {"Currency": "EUR", "Amount": 1000}}`))
+		case "/v2/test/users/1/transactions":
+			// A full first page, then a single transaction.
+			n := 1
+			if req.URL.Query().Get("page") == "1" {
+				n = 100
+			}
+			trs := make([]string, n)
+			for i := range trs {
+				trs[i] = `{"Id": "t", "DebitedFunds": {"Currency": "EUR", "Amount": 10}}`
+			}
+			fmt.Fprintf(w, "[%s]", strings.Join(trs, ","))
+		default:
+			test.Errorf("unexpected request %s", req.URL.Path)
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	})
+	t := m.NewKYCLimitTracker(DefaultKYCLimits)
+	used, skipped, err := t.used(m.UserRef("1"), "EUR", true)
+	if err != nil {
+		test.Fatal(err)
+	}
+	if used != 1000+101*10 || len(skipped) != 0 {
+		test.Errorf("expected %d used, got %d (skipped %v)", 1000+101*10, used, skipped)
+	}
+	used, skipped, err = t.used(m.UserRef("1"), "GBP", true)
+	if err != nil {
+		test.Fatal(err)
+	}
+	if used != 1000 || len(skipped) != 101 {
+		test.Errorf("expected pending transactions in EUR to be skipped, got %d used (skipped %d)",
+			used, len(skipped))
+	}
+}
+
+func TestKYCLimitMissingDocumentsPages(test *testing.T) {
+	m := newFakeService(test, func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path != "/v2/test/users/1/kyc/documents" {
+			test.Errorf("unexpected request %s", req.URL.Path)
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		// The validated document is on the second page.
+		if req.URL.Query().Get("page") == "1" {
+			docs := make([]string, 100)
+			for i := range docs {
+				docs[i] = `{"Id": "d", "Type": "IDENTITY_PROOF", "Status": "REFUSED"}`
+			}
+			fmt.Fprintf(w, "[%s]", strings.Join(docs, ","))
+			return
+		}
+		w.Write([]byte(`[{"Id": "v", "Type": "IDENTITY_PROOF", "Status": "VALIDATED"}]`))
+	})
+	u := &NaturalUser{}
+	u.Id = "1"
+	missing, err := m.NewKYCLimitTracker(DefaultKYCLimits).missingDocuments(u)
+	if err != nil {
+		test.Fatal(err)
+	}
+	if len(missing) != 0 {
+		test.Errorf("expected no missing documents, got %v", missing)
+	}
+}
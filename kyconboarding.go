@@ -0,0 +1,216 @@
+// Copyright 2014 Mathias Monnerville. All rights reserved.
+// Use of this source code is governed by a GPL
+// license that can be found in the LICENSE file.
+
+package mango
+
+import (
+	"errors"
+	"fmt"
+)
+
+// KYCDocumentProgress tracks a KYC document through upload, submission
+// and validation.
+type KYCDocumentProgress struct {
+	Type                 DocumentType
+	DocumentId           string         // Empty until the document is created
+	Status               DocumentStatus // Empty until the document is created
+	PagesUploaded        int
+	RefusedReasonType    DocumentRefusedReasonType
+	RefusedReasonMessage string
+}
+
+// KYCOnboardingStatus is the progress of a user's KYC onboarding. It can be
+// marshalled to JSON and stored, then passed to ResumeKYCOnboarding to go
+// on after a restart.
+type KYCOnboardingStatus struct {
+	UserId       string
+	PersonType   string
+	UserCategory UserCategory
+	Documents    []*KYCDocumentProgress
+}
+
+func (s *KYCOnboardingStatus) String() string {
+	return struct2string(s)
+}
+
+// Done returns true when all documents have been validated.
+func (s *KYCOnboardingStatus) Done() bool {
+	for _, d := range s.Documents {
+		if d.Status != DocumentStatusValidated {
+			return false
+		}
+	}
+	return true
+}
+
+// ToUpload returns the document types that must be uploaded, either
+// because they haven't been submitted yet or because they were refused.
+func (s *KYCOnboardingStatus) ToUpload() []DocumentType {
+	types := []DocumentType{}
+	for _, d := range s.Documents {
+		if d.Status == "" || d.Status == DocumentStatusCreated || d.Status == DocumentStatusRefused {
+			types = append(types, d.Type)
+		}
+	}
+	return types
+}
+
+// Refused returns the refused documents. See their RefusedReasonType and
+// RefusedReasonMessage for details.
+func (s *KYCOnboardingStatus) Refused() []*KYCDocumentProgress {
+	refused := []*KYCDocumentProgress{}
+	for _, d := range s.Documents {
+		if d.Status == DocumentStatusRefused {
+			refused = append(refused, d)
+		}
+	}
+	return refused
+}
+
+func (s *KYCOnboardingStatus) document(t DocumentType) *KYCDocumentProgress {
+	for _, d := range s.Documents {
+		if d.Type == t {
+			return d
+		}
+	}
+	return nil
+}
+
+// KYCOnboarding uploads and submits the documents required for a user to
+// reach the REGULAR KYC level, and tracks them until they are validated or
+// refused.
+type KYCOnboarding struct {
+	Status *KYCOnboardingStatus
+	// OnProgress, if set, is called every time Status changes so that it can
+	// be stored.
+	OnProgress func(*KYCOnboardingStatus)
+	service    *MangoPay
+}
+
+// NewKYCOnboarding starts the KYC onboarding of user. The documents to
+// provide are given by RequiredDocuments. Only OWNER users can be verified:
+// a PAYER must be upgraded first (see UpgradeToOwner).
+func (m *MangoPay) NewKYCOnboarding(user Person) (*KYCOnboarding, error) {
+	id := consumerId(user)
+	if id == "" {
+		return nil, errors.New("kyc onboarding: user has empty Id")
+	}
+	s := &KYCOnboardingStatus{
+		UserId:     id,
+		PersonType: user.GetPersonType(),
+		Documents:  []*KYCDocumentProgress{},
+	}
+	switch u := user.(type) {
+	case *NaturalUser:
+		s.UserCategory = u.UserCategory
+	case *LegalUser:
+		s.UserCategory = u.UserCategory
+	}
+	if s.UserCategory == UserCategoryPayer {
+		return nil, fmt.Errorf("kyc onboarding: user %s is a PAYER, only OWNER users can be verified", id)
+	}
+	for _, t := range RequiredDocuments(user) {
+		s.Documents = append(s.Documents, &KYCDocumentProgress{Type: t})
+	}
+	if len(s.Documents) == 0 {
+		return nil, fmt.Errorf("kyc onboarding: no required documents for user %s", id)
+	}
+	return &KYCOnboarding{Status: s, service: m}, nil
+}
+
+// ResumeKYCOnboarding goes on with a previously stored onboarding status.
+func (m *MangoPay) ResumeKYCOnboarding(status *KYCOnboardingStatus) (*KYCOnboarding, error) {
+	if status == nil || status.UserId == "" {
+		return nil, errors.New("kyc onboarding: status has empty user Id")
+	}
+	return &KYCOnboarding{Status: status, service: m}, nil
+}
+
+func (k *KYCOnboarding) progress() {
+	if k.OnProgress != nil {
+		k.OnProgress(k.Status)
+	}
+}
+
+// Upload creates a document of type t with one page per file, then asks
+// for its validation. Types not listed in the status, i.e ADDRESS_PROOF,
+// are added to it. A refused document is replaced with a new one.
+//
+// If a previous upload was interrupted, the document's status is fetched
+// from MangoPay first: pages already uploaded are skipped, and nothing is
+// done if the document was submitted in the meantime.
+func (k *KYCOnboarding) Upload(t DocumentType, pages ...[]byte) error {
+	msg := "kyc onboarding: "
+	if len(pages) == 0 {
+		return errors.New(msg + "no pages to upload")
+	}
+	d := k.Status.document(t)
+	if d == nil {
+		d = &KYCDocumentProgress{Type: t}
+		k.Status.Documents = append(k.Status.Documents, d)
+	}
+	switch d.Status {
+	case DocumentStatusValidationAsked, DocumentStatusValidated:
+		return fmt.Errorf("%sdocument %s already submitted", msg, t)
+	}
+	if d.DocumentId != "" && d.Status != DocumentStatusRefused {
+		// The saved status may be older than the document's one.
+		doc, err := k.service.Document(d.DocumentId)
+		if err != nil {
+			return err
+		}
+		k.update(d, doc)
+		if d.Status == DocumentStatusValidationAsked || d.Status == DocumentStatusValidated {
+			return nil
+		}
+	}
+	if d.Status == DocumentStatusRefused {
+		*d = KYCDocumentProgress{Type: t}
+	}
+
+	if d.DocumentId == "" {
+		doc, err := k.service.NewDocument(k.service.UserRef(k.Status.UserId), t, "")
+		if err != nil {
+			return err
+		}
+		d.DocumentId, d.Status = doc.Id, doc.Status
+		k.progress()
+	}
+	doc := &Document{UserId: k.Status.UserId, service: k.service}
+	doc.Id = d.DocumentId
+	for d.PagesUploaded < len(pages) {
+		if err := doc.CreatePage(pages[d.PagesUploaded]); err != nil {
+			return err
+		}
+		d.PagesUploaded++
+		k.progress()
+	}
+	if err := doc.Submit(DocumentStatusValidationAsked, ""); err != nil {
+		return err
+	}
+	k.update(d, doc)
+	return nil
+}
+
+// Refresh fetches the status of all submitted documents.
+func (k *KYCOnboarding) Refresh() error {
+	for _, d := range k.Status.Documents {
+		if d.Status != DocumentStatusValidationAsked {
+			continue
+		}
+		doc, err := k.service.Document(d.DocumentId)
+		if err != nil {
+			return err
+		}
+		k.update(d, doc)
+	}
+	return nil
+}
+
+func (k *KYCOnboarding) update(d *KYCDocumentProgress, doc *Document) {
+	d.Status = doc.Status
+	d.RefusedReasonType = doc.RefusedReasonType
+	d.RefusedReasonMessage = doc.RefusedReasonMessage
+	k.progress()
+}
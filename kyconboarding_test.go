@@ -0,0 +1,114 @@
+package mango
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+func TestKYCOnboardingStatus(test *testing.T) {
+	m := new(MangoPay)
+	u := &LegalUser{LegalPersonType: LegalPersonTypeSoletrader, UserCategory: UserCategoryPayer}
+	u.Id = "1"
+	if _, err := m.NewKYCOnboarding(u); err == nil {
+		test.Error("expected an error for a PAYER")
+	}
+	u.UserCategory = UserCategoryOwner
+	k, err := m.NewKYCOnboarding(u)
+	if err != nil {
+		test.Fatal(err)
+	}
+	if k.Status.UserCategory != UserCategoryOwner {
+		test.Errorf("expected category %s, got %s", UserCategoryOwner, k.Status.UserCategory)
+	}
+	if n := len(k.Status.ToUpload()); n != 2 {
+		test.Errorf("expected 2 documents to upload, got %d", n)
+	}
+	k.Status.Documents[0].Status = DocumentStatusValidated
+	k.Status.Documents[1].Status = DocumentStatusRefused
+	if k.Status.Done() || len(k.Status.Refused()) != 1 {
+		test.Errorf("unexpected status: %v", k.Status)
+	}
+
+	// The status must survive a restart.
+	b, err := json.Marshal(k.Status)
+	if err != nil {
+		test.Fatal(err)
+	}
+	var s KYCOnboardingStatus
+	if err := json.Unmarshal(b, &s); err != nil {
+		test.Fatal(err)
+	}
+	r, err := m.ResumeKYCOnboarding(&s)
+	if err != nil {
+		test.Fatal(err)
+	}
+	if up := r.Status.ToUpload(); len(up) != 1 || up[0] != RegistrationProof {
+		test.Errorf("expected %s to upload again, got %v", RegistrationProof, up)
+	}
+	if err := r.Upload(IdentityProof, []byte("page")); err == nil {
+		test.Error("expected an error when uploading a validated document")
+	}
+}
+
+func TestKYCOnboardingResumeSubmitted(test *testing.T) {
+	// The process died after the document was submitted but before the
+	// new status was saved.
+	m := newFakeService(test, func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != "GET" || req.URL.Path != "/v2/test/kyc/documents/d1" {
+			test.Errorf("unexpected request %s %s", req.Method, req.URL.Path)
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"Message": "unexpected request"}`))
+			return
+		}
+		w.Write([]byte(`{"Id": "d1", "UserId": "1", "Type": "IDENTITY_PROOF", "Status": "VALIDATION_ASKED"}`))
+	})
+	s := &KYCOnboardingStatus{UserId: "1", Documents: []*KYCDocumentProgress{
+		{Type: IdentityProof, DocumentId: "d1", Status: DocumentStatusCreated, PagesUploaded: 1},
+	}}
+	k, err := m.ResumeKYCOnboarding(s)
+	if err != nil {
+		test.Fatal(err)
+	}
+	if err := k.Upload(IdentityProof, []byte("page")); err != nil {
+		test.Fatal(err)
+	}
+	if d := k.Status.Documents[0]; d.Status != DocumentStatusValidationAsked {
+		test.Errorf("expected status to be synced, got %s", d.Status)
+	}
+	if len(k.Status.ToUpload()) != 0 {
+		test.Errorf("expected nothing to upload, got %v", k.Status.ToUpload())
+	}
+}
+
+func TestKYCOnboardingPagesProgress(test *testing.T) {
+	m := newFakeService(test, func(w http.ResponseWriter, req *http.Request) {
+		switch {
+		case req.Method == "GET":
+			w.Write([]byte(`{"Id": "d1", "UserId": "1", "Type": "IDENTITY_PROOF", "Status": "CREATED"}`))
+		case req.URL.Path == "/v2/test/users/1/kyc/documents/d1/pages":
+			w.Write([]byte(`{}`))
+		default:
+			w.Write([]byte(`{"Id": "d1", "UserId": "1", "Type": "IDENTITY_PROOF", "Status": "VALIDATION_ASKED"}`))
+		}
+	})
+	k, err := m.ResumeKYCOnboarding(&KYCOnboardingStatus{UserId: "1", Documents: []*KYCDocumentProgress{
+		{Type: IdentityProof, DocumentId: "d1", Status: DocumentStatusCreated},
+	}})
+	if err != nil {
+		test.Fatal(err)
+	}
+	// Pages uploaded as seen by the caller storing the status.
+	saved := []int{}
+	k.OnProgress = func(s *KYCOnboardingStatus) {
+		if d := s.Documents[0]; d.Status == DocumentStatusCreated {
+			saved = append(saved, d.PagesUploaded)
+		}
+	}
+	if err := k.Upload(IdentityProof, []byte("page 1"), []byte("page 2")); err != nil {
+		test.Fatal(err)
+	}
+	if len(saved) < 2 || saved[len(saved)-2] != 1 || saved[len(saved)-1] != 2 {
+		test.Errorf("expected the saved status to count each uploaded page, got %v", saved)
+	}
+}
@@ -6,6 +6,14 @@ package mango
 
 import (
 	"encoding/json"
+	"errors"
+)
+
+const (
+	LegalPersonTypeBusiness     = "BUSINESS"
+	LegalPersonTypeOrganization = "ORGANIZATION"
+	LegalPersonTypeSoletrader   = "SOLETRADER"
+	LegalPersonTypePartnership  = "PARTNERSHIP"
 )
 
 // LegalUser describes all the properties of a MangoPay legal user object.
@@ -13,11 +21,11 @@ type LegalUser struct {
 	User
 	Name                                  string
 	LegalPersonType                       string
-	UserCategory                          string
-	HeadquartersAddress                   string
+	UserCategory                          UserCategory
+	HeadquartersAddress                   *Address `json:",omitempty"`
 	LegalRepresentativeFirstName          string
 	LegalRepresentativeLastName           string
-	LegalRepresentativeAddress            string
+	LegalRepresentativeAddress            *Address `json:",omitempty"`
 	LegalRepresentativeEmail              string
 	LegalRepresentativeBirthday           int64
 	LegalRepresentativeNationality        string
@@ -36,7 +44,7 @@ func (u *LegalUser) String() string {
 }
 
 // NewLegalUser creates a new legal user.
-func (m *MangoPay) NewLegalUser(name string, email string, userCategory string, address string, companyNumber string, personType string, legalFirstName string, legalLastName string, birthday int64, nationality string, country string, terms bool) *LegalUser {
+func (m *MangoPay) NewLegalUser(name string, email string, userCategory UserCategory, address *Address, companyNumber string, personType string, legalFirstName string, legalLastName string, birthday int64, nationality string, country string, terms bool) *LegalUser {
 	u := &LegalUser{
 		Name:                                  name,
 		UserCategory:                          userCategory,
@@ -67,9 +75,58 @@ func (u *LegalUser) Transfers() (TransferList, error) {
 	return trs, err
 }
 
+// Validate checks that all fields required by the user's category are
+// filled. A PAYER needs a name, a legal person type, an email, the legal
+// representative's first and last name and must have accepted the terms
+// and conditions. An OWNER also needs a headquarters address, the legal
+// representative's birthday, nationality and country of residence and,
+// for businesses, a company number.
+func (u *LegalUser) Validate() error {
+	var f userFields
+	f.require("UserCategory", u.UserCategory == UserCategoryPayer || u.UserCategory == UserCategoryOwner)
+	f.require("Name", u.Name != "")
+	f.require("LegalPersonType", u.LegalPersonType != "")
+	f.require("Email", u.Email != "")
+	f.require("LegalRepresentativeFirstName", u.LegalRepresentativeFirstName != "")
+	f.require("LegalRepresentativeLastName", u.LegalRepresentativeLastName != "")
+	f.require("TermsAndConditionsAccepted", u.TermsAndConditionsAccepted)
+	if u.UserCategory == UserCategoryOwner {
+		f.require("HeadquartersAddress", !u.HeadquartersAddress.IsEmpty())
+		f.require("LegalRepresentativeBirthday", u.LegalRepresentativeBirthday != 0)
+		f.require("LegalRepresentativeNationality", u.LegalRepresentativeNationality != "")
+		f.require("LegalRepresentativeCountryOfResidence", u.LegalRepresentativeCountryOfResidence != "")
+		if u.LegalPersonType == LegalPersonTypeBusiness {
+			f.require("CompanyNumber", u.CompanyNumber != "")
+		}
+	}
+	return f.err(u.UserCategory)
+}
+
+// UpgradeToOwner turns a PAYER into an OWNER, allowing the user to receive
+// money and pay it out. Fields required for an OWNER must be filled before
+// the call.
+func (u *LegalUser) UpgradeToOwner() error {
+	if u.Id == "" {
+		return errors.New("user has empty Id")
+	}
+	previous := u.UserCategory
+	u.UserCategory = UserCategoryOwner
+	if err := u.Validate(); err != nil {
+		u.UserCategory = previous
+		return err
+	}
+	if err := u.Save(); err != nil {
+		u.UserCategory = previous
+		return err
+	}
+	return nil
+}
+
 // Save creates or updates a legal user. The Create API is used
 // if the user's Id is an empty string. The Edit API is used when
-// the Id is a non-empty string.
+// the Id is a non-empty string. Required fields and the addresses are
+// checked before creating the user (see Validate). Empty addresses are
+// not sent.
 func (u *LegalUser) Save() error {
 	var action mangoAction
 	if u.Id == "" {
@@ -78,6 +135,19 @@ func (u *LegalUser) Save() error {
 		action = actionEditLegalUser
 	}
 
+	if action == actionCreateLegalUser {
+		if err := u.Validate(); err != nil {
+			return err
+		}
+		for _, a := range []*Address{u.HeadquartersAddress, u.LegalRepresentativeAddress} {
+			if !a.IsEmpty() {
+				if err := a.Validate(); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
 	data := JsonObject{}
 	j, err := json.Marshal(u)
 	if err != nil {
@@ -92,11 +162,21 @@ func (u *LegalUser) Save() error {
 		data[field] = int(data[field].(float64))
 	}
 
-	// Fields not allowed when creating a user
+	// Fields not allowed when creating a user.
 	if action == actionCreateLegalUser {
 		delete(data, "Id")
 	}
+	// Read-only fields
 	delete(data, "CreationDate")
+	delete(data, "KYCLevel")
+	delete(data, "UserStatus")
+	delete(data, "TermsAndConditionsAcceptedDate")
+	if u.HeadquartersAddress.IsEmpty() {
+		delete(data, "HeadquartersAddress")
+	}
+	if u.LegalRepresentativeAddress.IsEmpty() {
+		delete(data, "LegalRepresentativeAddress")
+	}
 
 	if action == actionEditLegalUser {
 		// Delete empty values so that existing ones don't get
@@ -0,0 +1,163 @@
+// Copyright 2014 Mathias Monnerville. All rights reserved.
+// Use of this source code is governed by a GPL
+// license that can be found in the LICENSE file.
+
+package mango
+
+import (
+	"errors"
+)
+
+type MandateStatus string
+
+const (
+	MandateStatusCreated   MandateStatus = "CREATED"
+	MandateStatusSubmitted MandateStatus = "SUBMITTED"
+	MandateStatusActive    MandateStatus = "ACTIVE"
+	MandateStatusFailed    MandateStatus = "FAILED"
+	MandateStatusExpired   MandateStatus = "EXPIRED"
+)
+
+const (
+	MandateSchemeSEPA = "SEPA"
+	MandateSchemeBACS = "BACS"
+)
+
+// List of mandates.
+type MandateList []*Mandate
+
+// Mandate allows to collect direct debit payIns from a user's bank
+// account. Once created, the user must confirm the mandate by visiting
+// RedirectURL. The mandate can then be used once its Status is ACTIVE.
+//
+// See https://docs.mangopay.com/endpoints/v2.01/mandates
+type Mandate struct {
+	ProcessIdent
+	BankAccountId string
+	UserId        string
+	ReturnURL     string
+	RedirectURL   string
+	DocumentURL   string
+	Culture       string
+	Scheme        string // SEPA or BACS
+	Status        MandateStatus
+	ResultCode    string
+	ResultMessage string
+	ExecutionType string
+	MandateType   string
+	BankReference string
+
+	service *MangoPay
+}
+
+func (md *Mandate) String() string {
+	return struct2string(md)
+}
+
+// NewMandate creates a new direct debit mandate for an IBAN or GB bank
+// account. Call Save() to send it to MangoPay and get the RedirectURL the
+// user must visit to sign the mandate.
+func (m *MangoPay) NewMandate(account *BankAccount, returnURL, culture string) (*Mandate, error) {
+	msg := "new mandate: "
+	if account == nil {
+		return nil, errors.New(msg + "nil bank account")
+	}
+	if account.Id == "" {
+		return nil, errors.New(msg + "bank account has empty Id")
+	}
+	if account.Type != accountTypes[IBAN] && account.Type != accountTypes[GB] {
+		return nil, errors.New(msg + "bank account must be of type IBAN or GB")
+	}
+	if returnURL == "" {
+		return nil, errors.New(msg + "empty return url")
+	}
+	if culture == "" {
+		return nil, errors.New(msg + "empty culture")
+	}
+	md := &Mandate{
+		BankAccountId: account.Id,
+		UserId:        account.UserId,
+		ReturnURL:     returnURL,
+		Culture:       culture,
+		service:       m,
+	}
+	return md, nil
+}
+
+// Save sends an HTTP query to create the mandate.
+func (md *Mandate) Save() error {
+	data := JsonObject{
+		"BankAccountId": md.BankAccountId,
+		"ReturnURL":     md.ReturnURL,
+		"Culture":       md.Culture,
+	}
+	if md.Tag != "" {
+		data["Tag"] = md.Tag
+	}
+
+	ins, err := md.service.anyRequest(new(Mandate), actionCreateMandate, data)
+	if err != nil {
+		return err
+	}
+	serv := md.service
+	*md = *(ins.(*Mandate))
+	md.service = serv
+	return nil
+}
+
+// Cancel cancels the mandate. Only SUBMITTED or ACTIVE mandates can be
+// cancelled.
+func (md *Mandate) Cancel() error {
+	if md.Id == "" {
+		return errors.New("mandate has empty Id")
+	}
+	ins, err := md.service.anyRequest(new(Mandate), actionCancelMandate, JsonObject{"Id": md.Id})
+	if err != nil {
+		return err
+	}
+	serv := md.service
+	*md = *(ins.(*Mandate))
+	md.service = serv
+	return nil
+}
+
+// Mandate fetches a mandate.
+func (m *MangoPay) Mandate(id string) (*Mandate, error) {
+	any, err := m.anyRequest(new(Mandate), actionFetchMandate, JsonObject{"Id": id})
+	if err != nil {
+		return nil, err
+	}
+	md := any.(*Mandate)
+	md.service = m
+	return md, nil
+}
+
+// Mandates finds all user's mandates.
+func (m *MangoPay) Mandates(user Consumer) (MandateList, error) {
+	id := consumerId(user)
+	if id == "" {
+		return nil, errors.New("user has empty Id")
+	}
+	return m.mandates(actionFetchUserMandates, JsonObject{"UserId": id})
+}
+
+// BankAccountMandates finds all mandates of a bank account.
+func (m *MangoPay) BankAccountMandates(account *BankAccount) (MandateList, error) {
+	if account == nil || account.Id == "" {
+		return nil, errors.New("bank account has empty Id")
+	}
+	return m.mandates(actionFetchBankAccountMandates,
+		JsonObject{"UserId": account.UserId, "Id": account.Id})
+}
+
+func (m *MangoPay) mandates(action mangoAction, data JsonObject) (MandateList, error) {
+	list, err := m.anyRequest(new(MandateList), action, data)
+	if err != nil {
+		return nil, err
+	}
+	casted := *(list.(*MandateList))
+	for _, md := range casted {
+		md.service = m
+	}
+	return casted, nil
+}
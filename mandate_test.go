@@ -0,0 +1,33 @@
+package mango
+
+import (
+	"testing"
+)
+
+func TestMandate_Save(test *testing.T) {
+	serv := newTestService(test)
+	user := createTestUser(serv)
+	if err := user.Save(); err != nil {
+		test.Fatal("Unable to store user:", err)
+	}
+	account := createTestBankAccount(test, serv, user)
+
+	mandate, err := serv.NewMandate(account, "https://google.com", "EN")
+	if err != nil {
+		test.Fatal("Unable to create mandate:", err)
+	}
+	if err := mandate.Save(); err != nil {
+		test.Fatal("Unable to store mandate:", err)
+	}
+	if mandate.RedirectURL == "" {
+		test.Fatal("Missing mandate's redirect URL")
+	}
+
+	mandates, err := serv.BankAccountMandates(account)
+	if err != nil {
+		test.Fatal("Unable to list bank account's mandates:", err)
+	}
+	if len(mandates) != 1 || mandates[0].Id != mandate.Id {
+		test.Fatalf("Invalid bank account's mandates: got %v", mandates)
+	}
+}
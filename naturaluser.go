@@ -6,20 +6,22 @@ package mango
 
 import (
 	"encoding/json"
+	"errors"
 )
 
 // NaturalUser describes all the properties of a MangoPay natural user object.
 type NaturalUser struct {
 	User
 	FirstName, LastName        string
-	Address                    string
-	UserCategory               string
+	Address                    *Address `json:",omitempty"`
+	UserCategory               UserCategory
 	TermsAndConditionsAccepted bool
 	Birthday                   int64
 	Nationality                string
 	CountryOfResidence         string
 	Occupation                 string
 	IncomeRange                int
+	Capacity                   string `json:",omitempty"` // NORMAL or DECLARATIVE
 	ProofOfIdentity            string
 	ProofOfAddress             string
 	service                    *MangoPay // Current service
@@ -31,7 +33,7 @@ func (u *NaturalUser) String() string {
 }
 
 // NewNaturalUser creates a new natural user.
-func (m *MangoPay) NewNaturalUser(first, last string, email string, userCategory string, birthday int64, nationality, country string, terms bool) *NaturalUser {
+func (m *MangoPay) NewNaturalUser(first, last string, email string, userCategory UserCategory, birthday int64, nationality, country string, terms bool) *NaturalUser {
 	u := &NaturalUser{
 		FirstName:                  first,
 		LastName:                   last,
@@ -58,9 +60,51 @@ func (u *NaturalUser) Transfers() (TransferList, error) {
 	return trs, err
 }
 
+// Validate checks that all fields required by the user's category are
+// filled. A PAYER needs a first and last name, an email and must have
+// accepted the terms and conditions. An OWNER also needs an address, a
+// birthday, a nationality and a country of residence.
+func (u *NaturalUser) Validate() error {
+	var f userFields
+	f.require("UserCategory", u.UserCategory == UserCategoryPayer || u.UserCategory == UserCategoryOwner)
+	f.require("FirstName", u.FirstName != "")
+	f.require("LastName", u.LastName != "")
+	f.require("Email", u.Email != "")
+	f.require("TermsAndConditionsAccepted", u.TermsAndConditionsAccepted)
+	if u.UserCategory == UserCategoryOwner {
+		f.require("Address", !u.Address.IsEmpty())
+		f.require("Birthday", u.Birthday != 0)
+		f.require("Nationality", u.Nationality != "")
+		f.require("CountryOfResidence", u.CountryOfResidence != "")
+	}
+	return f.err(u.UserCategory)
+}
+
+// UpgradeToOwner turns a PAYER into an OWNER, allowing the user to receive
+// money and pay it out. Fields required for an OWNER must be filled before
+// the call.
+func (u *NaturalUser) UpgradeToOwner() error {
+	if u.Id == "" {
+		return errors.New("user has empty Id")
+	}
+	previous := u.UserCategory
+	u.UserCategory = UserCategoryOwner
+	if err := u.Validate(); err != nil {
+		u.UserCategory = previous
+		return err
+	}
+	if err := u.Save(); err != nil {
+		u.UserCategory = previous
+		return err
+	}
+	return nil
+}
+
 // Save creates or updates a natural user. The Create API is used
 // if the user's Id is an empty string. The Edit API is used when
-// the Id is a non-empty string.
+// the Id is a non-empty string. Required fields and the address are
+// checked before creating the user (see Validate). An empty address is
+// not sent.
 func (u *NaturalUser) Save() error {
 	var action mangoAction
 	if u.Id == "" {
@@ -69,6 +113,17 @@ func (u *NaturalUser) Save() error {
 		action = actionEditNaturalUser
 	}
 
+	if action == actionCreateNaturalUser {
+		if err := u.Validate(); err != nil {
+			return err
+		}
+		if !u.Address.IsEmpty() {
+			if err := u.Address.Validate(); err != nil {
+				return err
+			}
+		}
+	}
+
 	data := JsonObject{}
 	j, err := json.Marshal(u)
 	if err != nil {
@@ -83,11 +138,18 @@ func (u *NaturalUser) Save() error {
 		data[field] = int(data[field].(float64))
 	}
 
-	// Fields not allowed when creating a user
+	// Fields not allowed when creating a user.
 	if action == actionCreateNaturalUser {
 		delete(data, "Id")
 	}
+	// Read-only fields
 	delete(data, "CreationDate")
+	delete(data, "KYCLevel")
+	delete(data, "UserStatus")
+	delete(data, "TermsAndConditionsAcceptedDate")
+	if u.Address.IsEmpty() {
+		delete(data, "Address")
+	}
 
 	if action == actionEditNaturalUser {
 		// Delete empty values so that existing ones don't get
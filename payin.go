@@ -15,7 +15,10 @@ const (
 	TransactionNatureRegular     = "REGULAR"
 	TransactionNatureRepudiation = "REPUDIATION"
 	TransactionNatureRefund      = "REFUND"
-	TransactionNature            = "SETTLEMENT"
+	TransactionNatureSettlement  = "SETTLEMENT"
+
+	// Deprecated: use TransactionNatureSettlement.
+	TransactionNature = TransactionNatureSettlement
 )
 
 const (
@@ -35,6 +38,12 @@ const (
 	PayInPaymentTypeDirectDebit   = "DIRECT_DEBIT"
 	PayInPaymentTypePreauthorized = "PREAUTHORIZED"
 	PayInPaymentTypeBankWire      = "BANK_WIRE"
+	PayInPaymentTypePayPal        = "PAYPAL"
+	PayInPaymentTypeApplePay      = "APPLEPAY"
+	PayInPaymentTypeGooglePay     = "GOOGLE_PAY"
+	PayInPaymentTypeIdeal         = "IDEAL"
+	PayInPaymentTypeBancontact    = "BCMC"
+	PayInPaymentTypePayconiq      = "PAYCONIQ"
 )
 
 const (
@@ -55,8 +64,9 @@ const (
 )
 
 const (
-	SecureModeDefault = "DEFAULT"
-	SecureModeForce   = "FORCE"
+	SecureModeDefault  = "DEFAULT"
+	SecureModeForce    = "FORCE"
+	SecureModeNoChoice = "NO_CHOICE"
 )
 
 const (
@@ -106,12 +116,22 @@ func (p *PayIn) String() string {
 }
 
 // DirectPayIn is used to process a payment with registered (tokenized) cards.
+//
+// BrowserInfo and IpAddress are required by 3DS2 (see NewBrowserInfo and
+// IpAddressFromRequest). Billing and Shipping details are optional but
+// improve the chances of a frictionless authentication.
 type DirectPayIn struct {
 	PayIn
 	SecureModeReturnUrl   string
 	SecureModeRedirectURL string
+	SecureModeNeeded      bool
 	CardId                string
 	DebitedWalletId       string
+	BrowserInfo           *BrowserInfo `json:",omitempty"`
+	IpAddress             string       `json:",omitempty"`
+	Billing               *Billing     `json:",omitempty"`
+	Shipping              *Shipping    `json:",omitempty"`
+	StatementDescriptor   string       `json:",omitempty"`
 	service               *MangoPay
 }
 
@@ -301,10 +321,19 @@ func (p *DirectPayIn) Save() error {
 	// Fields not allowed when creating a tranfer.
 	for _, field := range []string{"Id", "CreationDate", "ExecutionDate", "CreditedFunds",
 		"ResultCode", "ResultMessage", "Status", "ExecutionType", "PaymentType",
-		"SecureMode", "DebitedWalletId", "Type", "Nature"} {
+		"SecureModeRedirectURL", "SecureModeNeeded", "DebitedWalletId", "Type", "Nature"} {
 
 		delete(data, field)
 	}
+	if err := checkSecureMode(p.SecureMode); err != nil {
+		return err
+	}
+	if p.SecureMode == "" {
+		delete(data, "SecureMode")
+	}
+	if err := checkStatementDescriptor(p.StatementDescriptor); err != nil {
+		return err
+	}
 
 	tr, err := p.service.anyRequest(new(DirectPayIn), actionCreateDirectPayIn, data)
 	if err != nil {
@@ -321,6 +350,30 @@ func (p *DirectPayIn) Save() error {
 	return nil
 }
 
+// checkSecureMode returns an error if mode is not a supported secure mode.
+// An empty mode is valid and lets MangoPay use SecureModeDefault.
+func checkSecureMode(mode string) error {
+	switch mode {
+	case "", SecureModeDefault, SecureModeForce, SecureModeNoChoice:
+		return nil
+	}
+	return fmt.Errorf("invalid secure mode %q", mode)
+}
+
+// checkStatementDescriptor returns an error if desc can't be used as a
+// statement descriptor: up to 10 alphanumeric characters.
+func checkStatementDescriptor(desc string) error {
+	if len(desc) > 10 {
+		return fmt.Errorf("statement descriptor %q is longer than 10 characters", desc)
+	}
+	for _, r := range desc {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9') {
+			return fmt.Errorf("statement descriptor %q must be alphanumeric", desc)
+		}
+	}
+	return nil
+}
+
 // Refund allows to refund a pay-in. Call the Refund's Save() method
 // to make a request to reimburse a user on his payment card.
 func (p *PayIn) Refund() (*Refund, error) {
@@ -511,3 +564,108 @@ func (t *DirectDebitWebPayIn) Save() error {
 	}
 	return nil
 }
+
+// NewDirectDebitDirectPayIn creates a direct debit payment from a bank
+// account, using a mandate previously signed by the user. The mandate must
+// be SUBMITTED or ACTIVE. The statement descriptor is optional and can be
+// up to 10 alphanumeric characters.
+//
+// See https://docs.mangopay.com/endpoints/v2.01/payins#e282_create-a-direct-debit-direct-payin
+func (m *MangoPay) NewDirectDebitDirectPayIn(author Consumer, mandate *Mandate, credited *Wallet, amount, fees Money, statementDescriptor string) (*DirectDebitDirectPayIn, error) {
+	const errorPrefix = "mango.MangoPay.NewDirectDebitDirectPayIn: "
+	if author == nil {
+		return nil, errors.New(errorPrefix + "Parameter 'author' is nil")
+	}
+	if mandate == nil {
+		return nil, errors.New(errorPrefix + "Parameter 'mandate' is nil")
+	}
+	if credited == nil {
+		return nil, errors.New(errorPrefix + "Parameter 'credited' is nil")
+	}
+	authorId := consumerId(author)
+	if authorId == "" {
+		return nil, errors.New(errorPrefix + "'author' has empty Id")
+	}
+	if mandate.Id == "" {
+		return nil, errors.New(errorPrefix + "'mandate' has empty Id")
+	}
+	if mandate.Status != MandateStatusActive && mandate.Status != MandateStatusSubmitted {
+		return nil, fmt.Errorf("%s'mandate' status must be SUBMITTED or ACTIVE, got %s",
+			errorPrefix, mandate.Status)
+	}
+	if err := checkStatementDescriptor(statementDescriptor); err != nil {
+		return nil, errors.New(errorPrefix + err.Error())
+	}
+
+	p := &DirectDebitDirectPayIn{
+		PayIn: PayIn{
+			AuthorId:         authorId,
+			DebitedFunds:     amount,
+			Fees:             fees,
+			CreditedWalletId: credited.Id,
+			service:          m,
+		},
+		MandateId:           mandate.Id,
+		StatementDescriptor: statementDescriptor,
+	}
+	return p, nil
+}
+
+// DirectDebitDirectPayIn is a payment debited from a bank account through
+// a mandate. Such a payIn stays CREATED until the debit settles, which
+// takes several days: use Refresh() to track its status.
+type DirectDebitDirectPayIn struct {
+	PayIn
+	MandateId           string
+	StatementDescriptor string `json:",omitempty"`
+	ChargeDate          int64  `json:",omitempty"`
+}
+
+func (p *DirectDebitDirectPayIn) String() string {
+	return struct2string(p)
+}
+
+// Save sends an HTTP query to create a direct debit payIn. Upon successful
+// creation, it may return an ErrPayInFailed error if the payment has failed.
+func (t *DirectDebitDirectPayIn) Save() error {
+	tr, err := t.service.createPayIn(t, actionCreateDirectDebitDirectPayIn, "CreditedUserId", "ChargeDate")
+	if err != nil {
+		return err
+	}
+	serv := t.service
+	*t = *(tr.(*DirectDebitDirectPayIn))
+	t.PayIn.service = serv
+	return t.failure()
+}
+
+// Refresh fetches the current state of the payIn. It returns an
+// ErrPayInFailed error if the debit has failed.
+func (t *DirectDebitDirectPayIn) Refresh() error {
+	if t.Id == "" {
+		return errors.New("payIn has empty Id")
+	}
+	tr, err := t.service.anyRequest(new(DirectDebitDirectPayIn), actionFetchPayIn, JsonObject{"Id": t.Id})
+	if err != nil {
+		return err
+	}
+	serv := t.service
+	*t = *(tr.(*DirectDebitDirectPayIn))
+	t.PayIn.service = serv
+	return t.failure()
+}
+
+// Pending returns true as long as the debit has not settled.
+func (t *DirectDebitDirectPayIn) Pending() bool {
+	return t.Status == TransactionStatusCreated
+}
+
+// DirectDebitDirectPayIn finds a direct debit payIn.
+func (m *MangoPay) DirectDebitDirectPayIn(id string) (*DirectDebitDirectPayIn, error) {
+	p, err := m.anyRequest(new(DirectDebitDirectPayIn), actionFetchPayIn, JsonObject{"Id": id})
+	if err != nil {
+		return nil, err
+	}
+	payIn := p.(*DirectDebitDirectPayIn)
+	payIn.PayIn.service = m
+	return payIn, nil
+}
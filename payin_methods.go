@@ -0,0 +1,405 @@
+// Copyright 2014 Mathias Monnerville. All rights reserved.
+// Use of this source code is governed by a GPL
+// license that can be found in the LICENSE file.
+
+package mango
+
+import (
+	"encoding/json"
+	"errors"
+	"net/url"
+)
+
+// Fields returned by MangoPay that can't be sent when creating a payIn.
+var payInReadOnlyFields = []string{"Id", "CreationDate", "ExecutionDate", "CreditedFunds",
+	"ResultCode", "ResultMessage", "Status", "ExecutionType", "PaymentType",
+	"SecureMode", "Type", "Nature"}
+
+// newPayIn checks the parameters common to all payIns and returns a PayIn
+// filled with them.
+func (m *MangoPay) newPayIn(msg string, author Consumer, credited *Wallet, amount, fees Money) (PayIn, error) {
+	if author == nil {
+		return PayIn{}, errors.New(msg + "nil author")
+	}
+	if credited == nil {
+		return PayIn{}, errors.New(msg + "nil dest wallet")
+	}
+	id := consumerId(author)
+	if id == "" {
+		return PayIn{}, errors.New(msg + "author has empty Id")
+	}
+	if credited.Id == "" {
+		return PayIn{}, errors.New(msg + "dest wallet has empty Id")
+	}
+	return PayIn{
+		AuthorId:         id,
+		DebitedFunds:     amount,
+		Fees:             fees,
+		CreditedWalletId: credited.Id,
+		service:          m,
+	}, nil
+}
+
+// parseReturnURL checks that returnURL is a valid, non-empty URL.
+func parseReturnURL(msg, returnURL string) (string, error) {
+	if returnURL == "" {
+		return "", errors.New(msg + "empty return url")
+	}
+	u, err := url.Parse(returnURL)
+	if err != nil {
+		return "", errors.New(msg + err.Error())
+	}
+	return u.String(), nil
+}
+
+// createPayIn sends the HTTP query creating payIn p with action. The
+// JSON fields in ignore are not sent. The created payIn is returned as a
+// new instance of p's type.
+func (m *MangoPay) createPayIn(p interface{}, action mangoAction, ignore ...string) (interface{}, error) {
+	data, err := payInData(p, ignore...)
+	if err != nil {
+		return nil, err
+	}
+	return m.anyRequest(p, action, data)
+}
+
+// payInData returns the body used to create payIn p, without its read-only
+// fields and the JSON fields in ignore.
+func payInData(p interface{}, ignore ...string) (JsonObject, error) {
+	data := JsonObject{}
+	j, err := json.Marshal(p)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(j, &data); err != nil {
+		return nil, err
+	}
+	for _, field := range append(append([]string{}, payInReadOnlyFields...), ignore...) {
+		delete(data, field)
+	}
+	return data, nil
+}
+
+// failure returns an ErrPayInFailed error if the payIn has failed.
+func (p *PayIn) failure() error {
+	if p.Status == "FAILED" {
+		return &ErrPayInFailed{p.Id, p.ResultMessage, p.ResultCode}
+	}
+	return nil
+}
+
+// PayPalWebPayIn is a payment made with a PayPal account. The user must be
+// redirected to RedirectURL to complete the payment.
+//
+// See https://docs.mangopay.com/endpoints/v2.01/payins#e1280_create-a-paypal-payin
+type PayPalWebPayIn struct {
+	PayIn
+	ReturnURL           string
+	RedirectURL         string
+	Culture             string `json:",omitempty"`
+	StatementDescriptor string `json:",omitempty"`
+}
+
+func (p *PayPalWebPayIn) String() string {
+	return struct2string(p)
+}
+
+// NewPayPalWebPayIn creates a new PayPal payment.
+func (m *MangoPay) NewPayPalWebPayIn(author Consumer, credited *Wallet, amount, fees Money, returnURL, culture string) (*PayPalWebPayIn, error) {
+	msg := "new PayPal payIn: "
+	payIn, err := m.newPayIn(msg, author, credited, amount, fees)
+	if err != nil {
+		return nil, err
+	}
+	u, err := parseReturnURL(msg, returnURL)
+	if err != nil {
+		return nil, err
+	}
+	return &PayPalWebPayIn{PayIn: payIn, ReturnURL: u, Culture: culture}, nil
+}
+
+// Save sends an HTTP query to create the payIn. Upon successful creation,
+// it may return an ErrPayInFailed error if the payment has failed.
+func (p *PayPalWebPayIn) Save() error {
+	if err := checkStatementDescriptor(p.StatementDescriptor); err != nil {
+		return err
+	}
+	ins, err := p.service.createPayIn(p, actionCreatePayPalWebPayIn, "RedirectURL")
+	if err != nil {
+		return err
+	}
+	serv := p.service
+	*p = *(ins.(*PayPalWebPayIn))
+	p.service = serv
+	return p.failure()
+}
+
+// ApplePayPaymentData holds the payment token returned by Apple Pay.
+type ApplePayPaymentData struct {
+	TransactionId string
+	Network       string // VISA, MASTERCARD etc.
+	TokenData     string
+}
+
+// ApplePayPayIn is a payment made with an Apple Pay token.
+//
+// See https://docs.mangopay.com/endpoints/v2.01/payins#e1179_create-an-apple-pay-payin
+type ApplePayPayIn struct {
+	PayIn
+	PaymentData         ApplePayPaymentData
+	StatementDescriptor string `json:",omitempty"`
+}
+
+func (p *ApplePayPayIn) String() string {
+	return struct2string(p)
+}
+
+// NewApplePayPayIn creates a new Apple Pay payment.
+func (m *MangoPay) NewApplePayPayIn(author Consumer, credited *Wallet, amount, fees Money, data ApplePayPaymentData) (*ApplePayPayIn, error) {
+	msg := "new Apple Pay payIn: "
+	payIn, err := m.newPayIn(msg, author, credited, amount, fees)
+	if err != nil {
+		return nil, err
+	}
+	if data.TransactionId == "" || data.Network == "" || data.TokenData == "" {
+		return nil, errors.New(msg + "incomplete payment data")
+	}
+	return &ApplePayPayIn{PayIn: payIn, PaymentData: data}, nil
+}
+
+// Save sends an HTTP query to create the payIn. Upon successful creation,
+// it may return an ErrPayInFailed error if the payment has failed.
+func (p *ApplePayPayIn) Save() error {
+	if err := checkStatementDescriptor(p.StatementDescriptor); err != nil {
+		return err
+	}
+	ins, err := p.service.createPayIn(p, actionCreateApplePayPayIn)
+	if err != nil {
+		return err
+	}
+	serv := p.service
+	*p = *(ins.(*ApplePayPayIn))
+	p.service = serv
+	return p.failure()
+}
+
+// GooglePayPayIn is a payment made with a Google Pay token. As for
+// DirectPayIn, BrowserInfo and IpAddress are required by 3DS2.
+//
+// See https://docs.mangopay.com/endpoints/v2.01/payins#e1300_create-a-google-pay-payin
+type GooglePayPayIn struct {
+	PayIn
+	PaymentData           string
+	SecureModeReturnURL   string
+	SecureModeRedirectURL string
+	BrowserInfo           *BrowserInfo `json:",omitempty"`
+	IpAddress             string       `json:",omitempty"`
+	Billing               *Billing     `json:",omitempty"`
+	Shipping              *Shipping    `json:",omitempty"`
+	StatementDescriptor   string       `json:",omitempty"`
+}
+
+func (p *GooglePayPayIn) String() string {
+	return struct2string(p)
+}
+
+// NewGooglePayPayIn creates a new Google Pay payment. paymentData is the
+// token returned by the Google Pay API.
+func (m *MangoPay) NewGooglePayPayIn(author Consumer, credited *Wallet, amount, fees Money, paymentData, returnURL string, browser *BrowserInfo, ipAddress string) (*GooglePayPayIn, error) {
+	msg := "new Google Pay payIn: "
+	payIn, err := m.newPayIn(msg, author, credited, amount, fees)
+	if err != nil {
+		return nil, err
+	}
+	if paymentData == "" {
+		return nil, errors.New(msg + "empty payment data")
+	}
+	u, err := parseReturnURL(msg, returnURL)
+	if err != nil {
+		return nil, err
+	}
+	if browser == nil {
+		return nil, errors.New(msg + "nil browser info")
+	}
+	if ipAddress == "" {
+		return nil, errors.New(msg + "empty IP address")
+	}
+	p := &GooglePayPayIn{
+		PayIn:               payIn,
+		PaymentData:         paymentData,
+		SecureModeReturnURL: u,
+		BrowserInfo:         browser,
+		IpAddress:           ipAddress,
+	}
+	return p, nil
+}
+
+// Save sends an HTTP query to create the payIn. Upon successful creation,
+// it may return an ErrPayInFailed error if the payment has failed.
+func (p *GooglePayPayIn) Save() error {
+	if err := checkStatementDescriptor(p.StatementDescriptor); err != nil {
+		return err
+	}
+	ins, err := p.service.createPayIn(p, actionCreateGooglePayPayIn, "SecureModeRedirectURL")
+	if err != nil {
+		return err
+	}
+	serv := p.service
+	*p = *(ins.(*GooglePayPayIn))
+	p.service = serv
+	return p.failure()
+}
+
+// IdealWebPayIn is a payment made through the iDEAL bank transfer scheme
+// (EUR only). The user must be redirected to RedirectURL to complete the
+// payment.
+//
+// See https://docs.mangopay.com/endpoints/v2.01/payins#e1415_create-an-ideal-payin
+type IdealWebPayIn struct {
+	PayIn
+	ReturnURL           string
+	RedirectURL         string
+	Bic                 string `json:",omitempty"`
+	BankName            string `json:",omitempty"`
+	StatementDescriptor string `json:",omitempty"`
+}
+
+func (p *IdealWebPayIn) String() string {
+	return struct2string(p)
+}
+
+// NewIdealWebPayIn creates a new iDEAL payment. bic is optional and selects
+// the user's bank upfront.
+func (m *MangoPay) NewIdealWebPayIn(author Consumer, credited *Wallet, amount, fees Money, returnURL, bic string) (*IdealWebPayIn, error) {
+	msg := "new iDEAL payIn: "
+	payIn, err := m.newPayIn(msg, author, credited, amount, fees)
+	if err != nil {
+		return nil, err
+	}
+	if amount.Currency != "EUR" {
+		return nil, errors.New(msg + "currency must be EUR")
+	}
+	u, err := parseReturnURL(msg, returnURL)
+	if err != nil {
+		return nil, err
+	}
+	return &IdealWebPayIn{PayIn: payIn, ReturnURL: u, Bic: bic}, nil
+}
+
+// Save sends an HTTP query to create the payIn. Upon successful creation,
+// it may return an ErrPayInFailed error if the payment has failed.
+func (p *IdealWebPayIn) Save() error {
+	if err := checkStatementDescriptor(p.StatementDescriptor); err != nil {
+		return err
+	}
+	ins, err := p.service.createPayIn(p, actionCreateIdealWebPayIn, "RedirectURL", "BankName")
+	if err != nil {
+		return err
+	}
+	serv := p.service
+	*p = *(ins.(*IdealWebPayIn))
+	p.service = serv
+	return p.failure()
+}
+
+// BancontactWebPayIn is a payment made with a Bancontact card or app (EUR
+// only). The user must be redirected to RedirectURL, or to DeepLinkURL on
+// mobile, to complete the payment.
+//
+// See https://docs.mangopay.com/endpoints/v2.01/payins#e1425_create-a-bancontact-payin
+type BancontactWebPayIn struct {
+	PayIn
+	ReturnURL           string
+	RedirectURL         string
+	DeepLinkURL         string
+	Culture             string `json:",omitempty"`
+	StatementDescriptor string `json:",omitempty"`
+}
+
+func (p *BancontactWebPayIn) String() string {
+	return struct2string(p)
+}
+
+// NewBancontactWebPayIn creates a new Bancontact payment.
+func (m *MangoPay) NewBancontactWebPayIn(author Consumer, credited *Wallet, amount, fees Money, returnURL, culture string) (*BancontactWebPayIn, error) {
+	msg := "new Bancontact payIn: "
+	payIn, err := m.newPayIn(msg, author, credited, amount, fees)
+	if err != nil {
+		return nil, err
+	}
+	if amount.Currency != "EUR" {
+		return nil, errors.New(msg + "currency must be EUR")
+	}
+	u, err := parseReturnURL(msg, returnURL)
+	if err != nil {
+		return nil, err
+	}
+	return &BancontactWebPayIn{PayIn: payIn, ReturnURL: u, Culture: culture}, nil
+}
+
+// Save sends an HTTP query to create the payIn. Upon successful creation,
+// it may return an ErrPayInFailed error if the payment has failed.
+func (p *BancontactWebPayIn) Save() error {
+	if err := checkStatementDescriptor(p.StatementDescriptor); err != nil {
+		return err
+	}
+	ins, err := p.service.createPayIn(p, actionCreateBancontactWebPayIn, "RedirectURL", "DeepLinkURL")
+	if err != nil {
+		return err
+	}
+	serv := p.service
+	*p = *(ins.(*BancontactWebPayIn))
+	p.service = serv
+	return p.failure()
+}
+
+// PayconiqWebPayIn is a payment made with the Payconiq app (EUR only). The
+// user must be redirected to RedirectURL, or to DeepLinkURL on mobile, to
+// complete the payment.
+//
+// See https://docs.mangopay.com/endpoints/v2.01/payins#e1396_create-a-payconiq-payin
+type PayconiqWebPayIn struct {
+	PayIn
+	ReturnURL   string
+	RedirectURL string
+	DeepLinkURL string
+	Country     string
+}
+
+func (p *PayconiqWebPayIn) String() string {
+	return struct2string(p)
+}
+
+// NewPayconiqWebPayIn creates a new Payconiq payment. country is the
+// user's ISO country code (i.e BE, LU or NL).
+func (m *MangoPay) NewPayconiqWebPayIn(author Consumer, credited *Wallet, amount, fees Money, returnURL, country string) (*PayconiqWebPayIn, error) {
+	msg := "new Payconiq payIn: "
+	payIn, err := m.newPayIn(msg, author, credited, amount, fees)
+	if err != nil {
+		return nil, err
+	}
+	if amount.Currency != "EUR" {
+		return nil, errors.New(msg + "currency must be EUR")
+	}
+	if country == "" {
+		return nil, errors.New(msg + "empty country")
+	}
+	u, err := parseReturnURL(msg, returnURL)
+	if err != nil {
+		return nil, err
+	}
+	return &PayconiqWebPayIn{PayIn: payIn, ReturnURL: u, Country: country}, nil
+}
+
+// Save sends an HTTP query to create the payIn. Upon successful creation,
+// it may return an ErrPayInFailed error if the payment has failed.
+func (p *PayconiqWebPayIn) Save() error {
+	ins, err := p.service.createPayIn(p, actionCreatePayconiqWebPayIn, "RedirectURL", "DeepLinkURL")
+	if err != nil {
+		return err
+	}
+	serv := p.service
+	*p = *(ins.(*PayconiqWebPayIn))
+	p.service = serv
+	return p.failure()
+}
@@ -0,0 +1,31 @@
+package mango
+
+import (
+	"testing"
+)
+
+func TestNewPaymentMethodPayIns(test *testing.T) {
+	serv := &MangoPay{}
+	user := &NaturalUser{User: User{ProcessIdent: ProcessIdent{Id: "u1"}}}
+	wallet := &Wallet{ProcessIdent: ProcessIdent{Id: "w1"}}
+	usd := Money{Currency: "USD", Amount: 1000}
+
+	if _, err := serv.NewIdealWebPayIn(user, wallet, EUR10, EUR0, "https://example.com", ""); err != nil {
+		test.Fatal("Unable to create iDEAL pay-in:", err)
+	}
+	if _, err := serv.NewIdealWebPayIn(user, wallet, usd, EUR0, "https://example.com", ""); err == nil {
+		test.Fatal("Expected error for non-EUR iDEAL pay-in")
+	}
+	if _, err := serv.NewPayconiqWebPayIn(user, wallet, EUR10, EUR0, "https://example.com", ""); err == nil {
+		test.Fatal("Expected error for Payconiq pay-in without country")
+	}
+	if _, err := serv.NewPayPalWebPayIn(user, wallet, usd, EUR0, "", "EN"); err == nil {
+		test.Fatal("Expected error for PayPal pay-in without return URL")
+	}
+	if _, err := serv.NewApplePayPayIn(user, wallet, EUR10, EUR0, ApplePayPaymentData{TransactionId: "t1"}); err == nil {
+		test.Fatal("Expected error for Apple Pay pay-in with incomplete payment data")
+	}
+	if _, err := serv.NewGooglePayPayIn(user, &Wallet{}, EUR10, EUR0, "token", "https://example.com", &BrowserInfo{}, "1.2.3.4"); err == nil {
+		test.Fatal("Expected error for Google Pay pay-in to wallet with empty Id")
+	}
+}
@@ -1,6 +1,7 @@
 package mango
 
 import (
+	"strings"
 	"testing"
 )
 
@@ -60,3 +61,14 @@ func createTestDirectDebitWebPayIn(test *testing.T, serv *MangoPay, user Consume
 	}
 	return payIn
 }
+
+func TestNewDirectDebitDirectPayIn_MandateStatus(test *testing.T) {
+	m := new(MangoPay)
+	mandate := &Mandate{Status: MandateStatusCreated}
+	mandate.Id = "1"
+	_, err := m.NewDirectDebitDirectPayIn(m.UserRef("1"), mandate, m.WalletRef("2"),
+		Money{"EUR", 1000}, Money{"EUR", 0}, "")
+	if err == nil || !strings.Contains(err.Error(), "must be SUBMITTED or ACTIVE, got CREATED") {
+		test.Errorf("expected a mandate status error, got %v", err)
+	}
+}
@@ -0,0 +1,273 @@
+// Copyright 2014 Mathias Monnerville. All rights reserved.
+// Use of this source code is governed by a GPL
+// license that can be found in the LICENSE file.
+
+package mango
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+)
+
+const (
+	PreAuthorizationStatusWaiting   = "WAITING"
+	PreAuthorizationStatusCanceled  = "CANCELED"
+	PreAuthorizationStatusExpired   = "EXPIRED"
+	PreAuthorizationStatusValidated = "VALIDATED"
+)
+
+// ErrPreAuthorizationFailed is custom error returned in case of failed
+// pre-authorization.
+type ErrPreAuthorizationFailed struct {
+	ID   string
+	Msg  string
+	Code string
+}
+
+func (e *ErrPreAuthorizationFailed) Error() string {
+	return fmt.Sprintf("pre-authorization %s failed: %s ", e.ID, e.Msg)
+}
+
+// List of pre-authorizations.
+type CardPreAuthorizationList []*CardPreAuthorization
+
+// CardPreAuthorization holds funds on a registered card for up to 7 days.
+// All or part of the held amount can then be captured with a preauthorized
+// payIn (see NewPreauthorizedPayIn).
+//
+// As for DirectPayIn, BrowserInfo and IpAddress are required by 3DS2.
+//
+// See https://docs.mangopay.com/endpoints/v2.01/preauthorizations
+type CardPreAuthorization struct {
+	ProcessReply
+	AuthorId              string
+	DebitedFunds          Money
+	RemainingFunds        Money
+	PaymentStatus         string // WAITING, CANCELED, EXPIRED or VALIDATED
+	ExecutionType         string
+	PaymentType           string
+	SecureMode            string
+	SecureModeNeeded      bool
+	SecureModeReturnURL   string
+	SecureModeRedirectURL string
+	CardId                string
+	PayInId               string
+	ExpirationDate        int64
+	BrowserInfo           *BrowserInfo `json:",omitempty"`
+	IpAddress             string       `json:",omitempty"`
+	Billing               *Billing     `json:",omitempty"`
+	Shipping              *Shipping    `json:",omitempty"`
+	StatementDescriptor   string       `json:",omitempty"`
+	service               *MangoPay
+}
+
+func (p *CardPreAuthorization) String() string {
+	return struct2string(p)
+}
+
+// NewCardPreAuthorization creates a new pre-authorization on a registered
+// card. secureMode is one of SecureModeDefault, SecureModeForce or
+// SecureModeNoChoice (defaults to SecureModeDefault when empty). The user is
+// sent back to returnUrl after 3DS authentication.
+func (m *MangoPay) NewCardPreAuthorization(author Consumer, card *Card, amount Money, secureMode, returnUrl string) (*CardPreAuthorization, error) {
+	msg := "new card pre-authorization: "
+	if author == nil {
+		return nil, errors.New(msg + "nil author")
+	}
+	if card == nil {
+		return nil, errors.New(msg + "nil card")
+	}
+	id := consumerId(author)
+	if id == "" {
+		return nil, errors.New(msg + "author has empty Id")
+	}
+	if card.Id == "" {
+		return nil, errors.New(msg + "card has empty Id")
+	}
+	if returnUrl == "" {
+		return nil, errors.New(msg + "empty return url")
+	}
+	u, err := url.Parse(returnUrl)
+	if err != nil {
+		return nil, errors.New(msg + err.Error())
+	}
+	if err := checkSecureMode(secureMode); err != nil {
+		return nil, errors.New(msg + err.Error())
+	}
+	if secureMode == "" {
+		secureMode = SecureModeDefault
+	}
+	p := &CardPreAuthorization{
+		AuthorId:            id,
+		DebitedFunds:        amount,
+		CardId:              card.Id,
+		SecureMode:          secureMode,
+		SecureModeReturnURL: u.String(),
+		service:             m,
+	}
+	return p, nil
+}
+
+// Save sends an HTTP query to create the pre-authorization. Upon successful
+// creation, it may return an ErrPreAuthorizationFailed error if the
+// pre-authorization has failed.
+func (p *CardPreAuthorization) Save() error {
+	data := JsonObject{}
+	j, err := json.Marshal(p)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(j, &data); err != nil {
+		return err
+	}
+
+	// Fields not allowed when creating a pre-authorization.
+	for _, field := range []string{"Id", "CreationDate", "ExecutionDate", "ResultCode",
+		"ResultMessage", "Status", "RemainingFunds", "PaymentStatus", "ExecutionType",
+		"PaymentType", "SecureModeNeeded", "SecureModeRedirectURL", "PayInId",
+		"ExpirationDate"} {
+
+		delete(data, field)
+	}
+	if err := checkStatementDescriptor(p.StatementDescriptor); err != nil {
+		return err
+	}
+
+	ins, err := p.service.anyRequest(new(CardPreAuthorization), actionCreateCardPreAuthorization, data)
+	if err != nil {
+		return err
+	}
+	serv := p.service
+	*p = *(ins.(*CardPreAuthorization))
+	p.service = serv
+
+	if p.Status == "FAILED" {
+		return &ErrPreAuthorizationFailed{p.Id, p.ResultMessage, p.ResultCode}
+	}
+	return nil
+}
+
+// Cancel releases the funds held by the pre-authorization. It can't be
+// used once a payIn has captured the funds.
+func (p *CardPreAuthorization) Cancel() error {
+	if p.Id == "" {
+		return errors.New("pre-authorization has empty Id")
+	}
+	ins, err := p.service.anyRequest(new(CardPreAuthorization), actionEditCardPreAuthorization,
+		JsonObject{"Id": p.Id, "PaymentStatus": PreAuthorizationStatusCanceled})
+	if err != nil {
+		return err
+	}
+	serv := p.service
+	*p = *(ins.(*CardPreAuthorization))
+	p.service = serv
+	return nil
+}
+
+// CardPreAuthorization fetches a pre-authorization.
+func (m *MangoPay) CardPreAuthorization(id string) (*CardPreAuthorization, error) {
+	any, err := m.anyRequest(new(CardPreAuthorization), actionFetchCardPreAuthorization, JsonObject{"Id": id})
+	if err != nil {
+		return nil, err
+	}
+	p := any.(*CardPreAuthorization)
+	p.service = m
+	return p, nil
+}
+
+// CardPreAuthorizations finds all user's pre-authorizations.
+func (m *MangoPay) CardPreAuthorizations(user Consumer) (CardPreAuthorizationList, error) {
+	id := consumerId(user)
+	if id == "" {
+		return nil, errors.New("user has empty Id")
+	}
+	return m.cardPreAuthorizations(actionFetchUserCardPreAuthorizations, id)
+}
+
+// CardPreAuthorizationsByCard finds all pre-authorizations made with a card.
+func (m *MangoPay) CardPreAuthorizationsByCard(card *Card) (CardPreAuthorizationList, error) {
+	if card == nil || card.Id == "" {
+		return nil, errors.New("card has empty Id")
+	}
+	return m.cardPreAuthorizations(actionFetchCardCardPreAuthorizations, card.Id)
+}
+
+func (m *MangoPay) cardPreAuthorizations(action mangoAction, id string) (CardPreAuthorizationList, error) {
+	list, err := m.anyRequest(new(CardPreAuthorizationList), action, JsonObject{"Id": id})
+	if err != nil {
+		return nil, err
+	}
+	casted := *(list.(*CardPreAuthorizationList))
+	for _, p := range casted {
+		p.service = m
+	}
+	return casted, nil
+}
+
+// PreauthorizedPayIn captures all or part of the funds held by a card
+// pre-authorization.
+//
+// See https://docs.mangopay.com/endpoints/v2.01/payins#e297_create-a-card-preauthorized-payin
+type PreauthorizedPayIn struct {
+	PayIn
+	PreauthorizationId string
+}
+
+func (p *PreauthorizedPayIn) String() string {
+	return struct2string(p)
+}
+
+// NewPreauthorizedPayIn creates a payIn capturing amount from the funds held
+// by preauth. The pre-authorization must have succeeded and still be
+// waiting for capture; amount can't exceed the held amount.
+func (m *MangoPay) NewPreauthorizedPayIn(preauth *CardPreAuthorization, credited *Wallet, amount, fees Money) (*PreauthorizedPayIn, error) {
+	const errorPrefix = "mango.MangoPay.NewPreauthorizedPayIn: "
+	if preauth == nil {
+		return nil, errors.New(errorPrefix + "Parameter 'preauth' is nil")
+	}
+	if credited == nil {
+		return nil, errors.New(errorPrefix + "Parameter 'credited' is nil")
+	}
+	if preauth.Id == "" {
+		return nil, errors.New(errorPrefix + "'preauth' has empty Id")
+	}
+	if credited.Id == "" {
+		return nil, errors.New(errorPrefix + "'credited' has empty Id")
+	}
+	if preauth.Status != TransactionStatusSucceeded || preauth.PaymentStatus != PreAuthorizationStatusWaiting {
+		return nil, errors.New(errorPrefix + "'preauth' is not waiting for capture")
+	}
+	if amount.Currency != preauth.DebitedFunds.Currency {
+		return nil, errors.New(errorPrefix + "currency mismatch with 'preauth'")
+	}
+	if amount.Amount > preauth.DebitedFunds.Amount {
+		return nil, errors.New(errorPrefix + "amount is higher than the pre-authorized amount")
+	}
+
+	p := &PreauthorizedPayIn{
+		PayIn: PayIn{
+			AuthorId:         preauth.AuthorId,
+			DebitedFunds:     amount,
+			Fees:             fees,
+			CreditedWalletId: credited.Id,
+			service:          m,
+		},
+		PreauthorizationId: preauth.Id,
+	}
+	return p, nil
+}
+
+// Save sends an HTTP query to create the preauthorized payIn. Upon successful
+// creation, it may return an ErrPayInFailed error if the payment has failed.
+func (t *PreauthorizedPayIn) Save() error {
+	tr, err := t.service.createPayIn(t, actionCreatePreauthorizedPayIn)
+	if err != nil {
+		return err
+	}
+	serv := t.service
+	*t = *(tr.(*PreauthorizedPayIn))
+	t.PayIn.service = serv
+	return t.failure()
+}
@@ -0,0 +1,35 @@
+package mango
+
+import (
+	"testing"
+)
+
+func TestNewPreauthorizedPayIn(test *testing.T) {
+	serv := &MangoPay{}
+	wallet := &Wallet{ProcessIdent: ProcessIdent{Id: "w1"}}
+	preauth := &CardPreAuthorization{
+		ProcessReply:  ProcessReply{ProcessIdent: ProcessIdent{Id: "p1"}, Status: TransactionStatusSucceeded},
+		AuthorId:      "u1",
+		DebitedFunds:  EUR100,
+		PaymentStatus: PreAuthorizationStatusWaiting,
+	}
+
+	payIn, err := serv.NewPreauthorizedPayIn(preauth, wallet, EUR10, EUR0)
+	if err != nil {
+		test.Fatal("Unable to create preauthorized pay-in:", err)
+	}
+	if payIn.AuthorId != "u1" || payIn.PreauthorizationId != "p1" || payIn.CreditedWalletId != "w1" {
+		test.Fatalf("Invalid preauthorized pay-in: %v", payIn)
+	}
+
+	if _, err := serv.NewPreauthorizedPayIn(preauth, wallet, Money{Currency: "EUR", Amount: 10001}, EUR0); err == nil {
+		test.Fatal("Expected error when capturing more than the held amount")
+	}
+	if _, err := serv.NewPreauthorizedPayIn(preauth, wallet, Money{Currency: "USD", Amount: 100}, EUR0); err == nil {
+		test.Fatal("Expected error on currency mismatch")
+	}
+	preauth.PaymentStatus = PreAuthorizationStatusCanceled
+	if _, err := serv.NewPreauthorizedPayIn(preauth, wallet, EUR10, EUR0); err == nil {
+		test.Fatal("Expected error on canceled pre-authorization")
+	}
+}
@@ -0,0 +1,350 @@
+// Copyright 2014 Mathias Monnerville. All rights reserved.
+// Use of this source code is governed by a GPL
+// license that can be found in the LICENSE file.
+
+package mango
+
+import (
+	"encoding/json"
+	"errors"
+	"net/url"
+	"time"
+)
+
+const (
+	RecurringFrequencyDaily       = "Daily"
+	RecurringFrequencyWeekly      = "Weekly"
+	RecurringFrequencyTwiceAMonth = "TwiceAMonth"
+	RecurringFrequencyMonthly     = "Monthly"
+	RecurringFrequencyBimonthly   = "Bimonthly"
+	RecurringFrequencyQuarterly   = "Quarterly"
+	RecurringFrequencySemiannual  = "Semiannual"
+	RecurringFrequencyAnnual      = "Annual"
+	RecurringFrequencyBiannual    = "Biannual"
+)
+
+const (
+	RecurringStatusCreated              = "CREATED"
+	RecurringStatusAuthenticationNeeded = "AUTHENTICATION_NEEDED"
+	RecurringStatusInProgress           = "IN_PROGRESS"
+	RecurringStatusEnded                = "ENDED"
+)
+
+// RecurringPayInState describes the payIns already made for a recurring
+// registration.
+type RecurringPayInState struct {
+	PayinsLinked           int
+	CumulatedDebitedAmount Money
+	CumulatedFeesAmount    Money
+	LastPayinId            string
+}
+
+// RecurringPayInRegistration holds the details of a card subscription. The
+// first payIn is customer-initiated (CIT) and authenticated with 3DS; the
+// next ones are merchant-initiated (MIT) and don't require the user.
+//
+// See https://docs.mangopay.com/endpoints/v2.01/payins#e1053_the-recurring-payin-registration-object
+type RecurringPayInRegistration struct {
+	ProcessIdent
+	Status                       string
+	ResultCode                   string
+	ResultMessage                string
+	AuthorId                     string
+	CardId                       string
+	CreditedUserId               string `json:",omitempty"`
+	CreditedWalletId             string
+	FirstTransactionDebitedFunds Money
+	FirstTransactionFees         Money
+	NextTransactionDebitedFunds  *Money `json:",omitempty"`
+	NextTransactionFees          *Money `json:",omitempty"`
+	Frequency                    string
+	EndDate                      int64
+	FixedNextAmount              bool
+	FractionedPayment            bool
+	Billing                      *Billing             `json:",omitempty"`
+	Shipping                     *Shipping            `json:",omitempty"`
+	CurrentState                 *RecurringPayInState `json:",omitempty"`
+	RecurringType                string
+	TotalAmount                  *Money `json:",omitempty"`
+	CycleNumber                  int
+	service                      *MangoPay
+}
+
+func (r *RecurringPayInRegistration) String() string {
+	return struct2string(r)
+}
+
+// NewRecurringPayInRegistration creates a new card subscription crediting
+// wallet every frequency (see RecurringFrequency* constants) until
+// endDate (a Unix timestamp, 0 for no end). Next payIns use the amount of the first one unless
+// NextTransactionDebitedFunds and NextTransactionFees are set before
+// calling Save().
+func (m *MangoPay) NewRecurringPayInRegistration(author Consumer, card *Card, wallet *Wallet, amount, fees Money, frequency string, endDate int64) (*RecurringPayInRegistration, error) {
+	msg := "new recurring payIn registration: "
+	if author == nil {
+		return nil, errors.New(msg + "nil author")
+	}
+	if card == nil {
+		return nil, errors.New(msg + "nil card")
+	}
+	if wallet == nil {
+		return nil, errors.New(msg + "nil wallet")
+	}
+	id := consumerId(author)
+	if id == "" {
+		return nil, errors.New(msg + "author has empty Id")
+	}
+	if card.Id == "" {
+		return nil, errors.New(msg + "card has empty Id")
+	}
+	if wallet.Id == "" {
+		return nil, errors.New(msg + "wallet has empty Id")
+	}
+	if frequency == "" {
+		return nil, errors.New(msg + "empty frequency")
+	}
+	if endDate != 0 && endDate < time.Now().Unix() {
+		return nil, errors.New(msg + "end date is in the past")
+	}
+	r := &RecurringPayInRegistration{
+		AuthorId:                     id,
+		CardId:                       card.Id,
+		CreditedWalletId:             wallet.Id,
+		FirstTransactionDebitedFunds: amount,
+		FirstTransactionFees:         fees,
+		Frequency:                    frequency,
+		EndDate:                      endDate,
+		FixedNextAmount:              true,
+		service:                      m,
+	}
+	return r, nil
+}
+
+// Save creates or updates a recurring payIn registration. The Create API is
+// used if the registration's Id is an empty string. Otherwise, only the
+// card, billing, shipping and status can be updated.
+func (r *RecurringPayInRegistration) Save() error {
+	action, data, err := r.data()
+	if err != nil {
+		return err
+	}
+	ins, err := r.service.anyRequest(new(RecurringPayInRegistration), action, data)
+	if err != nil {
+		return err
+	}
+	serv := r.service
+	*r = *(ins.(*RecurringPayInRegistration))
+	r.service = serv
+	return nil
+}
+
+// data returns the action and the body used to save the registration.
+func (r *RecurringPayInRegistration) data() (mangoAction, JsonObject, error) {
+	data := JsonObject{}
+	if r.Id != "" {
+		data["Id"] = r.Id
+		data["CardId"] = r.CardId
+		if r.Billing != nil {
+			data["Billing"] = r.Billing
+		}
+		if r.Shipping != nil {
+			data["Shipping"] = r.Shipping
+		}
+		if r.Status == RecurringStatusEnded {
+			data["Status"] = r.Status
+		}
+		if r.Tag != "" {
+			data["Tag"] = r.Tag
+		}
+		return actionEditRecurringPayInRegistration, data, nil
+	}
+
+	j, err := json.Marshal(r)
+	if err != nil {
+		return 0, nil, err
+	}
+	if err := json.Unmarshal(j, &data); err != nil {
+		return 0, nil, err
+	}
+	// Fields not allowed when creating a registration.
+	for _, field := range []string{"Id", "CreationDate", "Status", "ResultCode",
+		"ResultMessage", "CurrentState", "RecurringType", "TotalAmount", "CycleNumber"} {
+		delete(data, field)
+	}
+	if r.EndDate == 0 {
+		delete(data, "EndDate")
+	}
+	return actionCreateRecurringPayInRegistration, data, nil
+}
+
+// ChangeCard updates the card debited by the next payIns.
+func (r *RecurringPayInRegistration) ChangeCard(card *Card) error {
+	if card == nil || card.Id == "" {
+		return errors.New("card has empty Id")
+	}
+	if r.Id == "" {
+		return errors.New("recurring payIn registration has empty Id")
+	}
+	previous := r.CardId
+	r.CardId = card.Id
+	if err := r.Save(); err != nil {
+		r.CardId = previous
+		return err
+	}
+	return nil
+}
+
+// End ends the subscription. No further payIn can be made.
+func (r *RecurringPayInRegistration) End() error {
+	if r.Id == "" {
+		return errors.New("recurring payIn registration has empty Id")
+	}
+	previous := r.Status
+	r.Status = RecurringStatusEnded
+	if err := r.Save(); err != nil {
+		r.Status = previous
+		return err
+	}
+	return nil
+}
+
+// PayIns lists the payIns made for the registration.
+func (r *RecurringPayInRegistration) PayIns() (RecurringPayInList, error) {
+	if r.Id == "" {
+		return nil, errors.New("recurring payIn registration has empty Id")
+	}
+	list, err := r.service.anyRequest(new(RecurringPayInList), actionFetchRecurringPayInRegistrationPayIns, JsonObject{"Id": r.Id})
+	if err != nil {
+		return nil, err
+	}
+	casted := *(list.(*RecurringPayInList))
+	for _, p := range casted {
+		p.PayIn.service = r.service
+	}
+	return casted, nil
+}
+
+// RecurringPayInRegistration fetches a recurring payIn registration.
+func (m *MangoPay) RecurringPayInRegistration(id string) (*RecurringPayInRegistration, error) {
+	any, err := m.anyRequest(new(RecurringPayInRegistration), actionFetchRecurringPayInRegistration, JsonObject{"Id": id})
+	if err != nil {
+		return nil, err
+	}
+	r := any.(*RecurringPayInRegistration)
+	r.service = m
+	return r, nil
+}
+
+// List of recurring payIns.
+type RecurringPayInList []*RecurringPayIn
+
+// RecurringPayIn is a card payIn made for a recurring registration, either
+// customer-initiated (see NewCustomerInitiatedPayIn) or merchant-initiated
+// (see NewMerchantInitiatedPayIn).
+type RecurringPayIn struct {
+	PayIn
+	RecurringPayinRegistrationId string
+	CardId                       string
+	SecureModeReturnURL          string       `json:",omitempty"`
+	SecureModeRedirectURL        string       `json:",omitempty"`
+	SecureModeNeeded             bool         `json:",omitempty"`
+	BrowserInfo                  *BrowserInfo `json:",omitempty"`
+	IpAddress                    string       `json:",omitempty"`
+	StatementDescriptor          string       `json:",omitempty"`
+}
+
+func (p *RecurringPayIn) String() string {
+	return struct2string(p)
+}
+
+// NewCustomerInitiatedPayIn creates the first payIn of the subscription,
+// with the user present for 3DS authentication. It debits
+// FirstTransactionDebitedFunds.
+func (r *RecurringPayInRegistration) NewCustomerInitiatedPayIn(browser *BrowserInfo, ipAddress, returnUrl string) (*RecurringPayIn, error) {
+	msg := "new customer-initiated recurring payIn: "
+	if r.Id == "" {
+		return nil, errors.New(msg + "registration has empty Id")
+	}
+	if browser == nil {
+		return nil, errors.New(msg + "nil browser info")
+	}
+	if ipAddress == "" {
+		return nil, errors.New(msg + "empty IP address")
+	}
+	if returnUrl == "" {
+		return nil, errors.New(msg + "empty return url")
+	}
+	u, err := url.Parse(returnUrl)
+	if err != nil {
+		return nil, errors.New(msg + err.Error())
+	}
+	p := &RecurringPayIn{
+		PayIn: PayIn{
+			DebitedFunds: r.FirstTransactionDebitedFunds,
+			Fees:         r.FirstTransactionFees,
+			service:      r.service,
+		},
+		RecurringPayinRegistrationId: r.Id,
+		SecureModeReturnURL:          u.String(),
+		BrowserInfo:                  browser,
+		IpAddress:                    ipAddress,
+	}
+	return p, nil
+}
+
+// NewMerchantInitiatedPayIn creates a follow-up payIn of the subscription,
+// without the user. Zero amount and fees let MangoPay use the
+// registration's next transaction amounts.
+func (r *RecurringPayInRegistration) NewMerchantInitiatedPayIn(amount, fees Money) (*RecurringPayIn, error) {
+	msg := "new merchant-initiated recurring payIn: "
+	if r.Id == "" {
+		return nil, errors.New(msg + "registration has empty Id")
+	}
+	if r.Status == RecurringStatusEnded {
+		return nil, errors.New(msg + "registration has ended")
+	}
+	p := &RecurringPayIn{
+		PayIn: PayIn{
+			DebitedFunds: amount,
+			Fees:         fees,
+			service:      r.service,
+		},
+		RecurringPayinRegistrationId: r.Id,
+	}
+	return p, nil
+}
+
+// Save sends an HTTP query to create the recurring payIn. Upon successful
+// creation, it may return an ErrPayInFailed error if the payment has failed.
+func (p *RecurringPayIn) Save() error {
+	if err := checkStatementDescriptor(p.StatementDescriptor); err != nil {
+		return err
+	}
+	data, err := p.data()
+	if err != nil {
+		return err
+	}
+	tr, err := p.service.anyRequest(new(RecurringPayIn), actionCreateRecurringPayIn, data)
+	if err != nil {
+		return err
+	}
+	serv := p.service
+	*p = *(tr.(*RecurringPayIn))
+	p.service = serv
+	return p.failure()
+}
+
+// data returns the body used to create the payIn. Zero amount and fees
+// are not sent so that MangoPay uses the registration's ones.
+func (p *RecurringPayIn) data() (JsonObject, error) {
+	data, err := payInData(p, "SecureModeRedirectURL", "SecureModeNeeded", "AuthorId",
+		"CreditedUserId", "CreditedWalletId", "CardId")
+	if err != nil {
+		return nil, err
+	}
+	if p.DebitedFunds.Amount == 0 && p.Fees.Amount == 0 {
+		delete(data, "DebitedFunds")
+		delete(data, "Fees")
+	}
+	return data, nil
+}
@@ -0,0 +1,92 @@
+package mango
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestRecurringPayInRegistrationData(test *testing.T) {
+	r := &RecurringPayInRegistration{
+		AuthorId:                     "1",
+		CardId:                       "2",
+		CreditedWalletId:             "3",
+		FirstTransactionDebitedFunds: Money{"EUR", 1000},
+		Frequency:                    RecurringFrequencyMonthly,
+		Status:                       RecurringStatusInProgress,
+		Billing:                      &Billing{FirstName: "Sergey"},
+	}
+	action, data, err := r.data()
+	if err != nil {
+		test.Fatal(err)
+	}
+	if action != actionCreateRecurringPayInRegistration {
+		test.Errorf("expected the create action, got %d", action)
+	}
+	for _, field := range []string{"Id", "Status", "EndDate", "CurrentState"} {
+		if _, ok := data[field]; ok {
+			test.Errorf("field %s must not be sent on creation", field)
+		}
+	}
+
+	r.Id = "4"
+	r.Tag = "tag"
+	action, data, err = r.data()
+	if err != nil {
+		test.Fatal(err)
+	}
+	if action != actionEditRecurringPayInRegistration {
+		test.Errorf("expected the edit action, got %d", action)
+	}
+	allowed := map[string]bool{"Id": true, "CardId": true, "Billing": true,
+		"Shipping": true, "Status": true, "Tag": true}
+	for field := range data {
+		if !allowed[field] {
+			test.Errorf("field %s must not be sent on update", field)
+		}
+	}
+	if _, ok := data["Status"]; ok {
+		test.Error("only the ENDED status can be sent")
+	}
+}
+
+func TestRecurringPayInRegistrationRollback(test *testing.T) {
+	m := newFakeService(test, func(w http.ResponseWriter, req *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"Message": "bad request"}`))
+	})
+	r := &RecurringPayInRegistration{CardId: "2", Status: RecurringStatusInProgress, service: m}
+	r.Id = "1"
+	card := &Card{}
+	card.Id = "3"
+	if err := r.ChangeCard(card); err == nil || r.CardId != "2" {
+		test.Errorf("expected card to be restored on error, got %s (%v)", r.CardId, err)
+	}
+	if err := r.End(); err == nil || r.Status != RecurringStatusInProgress {
+		test.Errorf("expected status to be restored on error, got %s (%v)", r.Status, err)
+	}
+}
+
+func TestRecurringPayInData(test *testing.T) {
+	r := &RecurringPayInRegistration{}
+	r.Id = "1"
+	p, err := r.NewMerchantInitiatedPayIn(Money{"EUR", 0}, Money{"EUR", 0})
+	if err != nil {
+		test.Fatal(err)
+	}
+	data, err := p.data()
+	if err != nil {
+		test.Fatal(err)
+	}
+	for _, field := range []string{"DebitedFunds", "Fees"} {
+		if _, ok := data[field]; ok {
+			test.Errorf("zero %s must not be sent", field)
+		}
+	}
+	p.DebitedFunds.Amount = 1000
+	if data, err = p.data(); err != nil {
+		test.Fatal(err)
+	}
+	if _, ok := data["DebitedFunds"]; !ok {
+		test.Error("non-zero DebitedFunds must be sent")
+	}
+}
@@ -0,0 +1,145 @@
+// Copyright 2014 Mathias Monnerville. All rights reserved.
+// Use of this source code is governed by a GPL
+// license that can be found in the LICENSE file.
+
+package mango
+
+import (
+	"encoding/json"
+	"errors"
+)
+
+// Repudiation is the transaction created by MangoPay when a dispute is
+// lost: the disputed funds are debited from the credited wallet of the
+// initial payIn.
+//
+// See https://docs.mangopay.com/endpoints/v2.01/repudiations
+type Repudiation struct {
+	ProcessReply
+	AuthorId               string
+	DebitedFunds           Money
+	Fees                   Money
+	CreditedFunds          Money
+	Type                   string // Always PAYOUT
+	Nature                 string // REPUDIATION
+	DebitedWalletId        string
+	DisputeId              string
+	InitialTransactionId   string
+	InitialTransactionType string
+	service                *MangoPay
+}
+
+func (r *Repudiation) String() string {
+	return struct2string(r)
+}
+
+// Repudiation fetches a repudiation.
+func (m *MangoPay) Repudiation(id string) (*Repudiation, error) {
+	any, err := m.anyRequest(new(Repudiation), actionFetchRepudiation, JsonObject{"Id": id})
+	if err != nil {
+		return nil, err
+	}
+	r := any.(*Repudiation)
+	r.service = m
+	return r, nil
+}
+
+// Refunds lists all refunds made against the repudiation.
+func (r *Repudiation) Refunds() (RefundList, error) {
+	if r.Id == "" {
+		return nil, errors.New("repudiation has empty Id")
+	}
+	list, err := r.service.anyRequest(new(RefundList), actionFetchRepudiationRefunds, JsonObject{"Id": r.Id})
+	if err != nil {
+		return nil, err
+	}
+	return *(list.(*RefundList)), nil
+}
+
+// SettlementTransfer is a transfer used to recover the funds lost after
+// a repudiation, debiting the wallet of the user responsible for it.
+//
+// See https://docs.mangopay.com/endpoints/v2.01/settlement-transfers
+type SettlementTransfer struct {
+	Transfer
+	RepudiationId string
+}
+
+func (t *SettlementTransfer) String() string {
+	return struct2string(t)
+}
+
+// NewSettlementTransfer creates a new settlement transfer for the given
+// repudiation. Call Save() to execute it.
+func (m *MangoPay) NewSettlementTransfer(author Consumer, repudiation *Repudiation, amount Money, fees Money) (*SettlementTransfer, error) {
+	msg := "new settlement transfer: "
+	if author == nil {
+		return nil, errors.New(msg + "nil author")
+	}
+	if repudiation == nil {
+		return nil, errors.New(msg + "nil repudiation")
+	}
+	if repudiation.Id == "" {
+		return nil, errors.New(msg + "repudiation has empty Id")
+	}
+	id := consumerId(author)
+	if id == "" {
+		return nil, errors.New(msg + "author has empty Id")
+	}
+	t := &SettlementTransfer{
+		Transfer: Transfer{
+			AuthorId:     id,
+			DebitedFunds: amount,
+			Fees:         fees,
+			service:      m,
+		},
+		RepudiationId: repudiation.Id,
+	}
+	return t, nil
+}
+
+// Save sends an HTTP query to create a settlement transfer. Upon successful
+// creation, it may return an ErrTransferFailed error if the transaction has
+// been rejected.
+func (t *SettlementTransfer) Save() error {
+	data := JsonObject{}
+	j, err := json.Marshal(t)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(j, &data); err != nil {
+		return err
+	}
+
+	// Fields not allowed when creating a settlement transfer.
+	for _, field := range []string{"Id", "CreationDate", "ExecutionDate", "CreditedFunds",
+		"CreditedUserId", "ResultCode", "ResultMessage", "Status",
+		"DebitedWalletId", "CreditedWalletId"} {
+
+		delete(data, field)
+	}
+
+	tr, err := t.service.anyRequest(new(SettlementTransfer), actionCreateSettlementTransfer, data)
+	if err != nil {
+		return err
+	}
+	serv := t.service
+	*t = *(tr.(*SettlementTransfer))
+	t.service = serv
+
+	if t.Status == "FAILED" {
+		return &ErrTransferFailed{t.Id, t.ResultMessage}
+	}
+	return nil
+}
+
+// SettlementTransfer finds a settlement transfer by id.
+func (m *MangoPay) SettlementTransfer(id string) (*SettlementTransfer, error) {
+	any, err := m.anyRequest(new(SettlementTransfer), actionFetchSettlementTransfer, JsonObject{"Id": id})
+	if err != nil {
+		return nil, err
+	}
+	t := any.(*SettlementTransfer)
+	t.service = m
+	return t, nil
+}
@@ -0,0 +1,32 @@
+package mango
+
+import "testing"
+
+func TestNewSettlementTransfer(test *testing.T) {
+	m := new(MangoPay)
+	author := m.UserRef("1")
+	repudiation := &Repudiation{}
+	repudiation.Id = "2"
+	amount := Money{"EUR", 100}
+	for _, tt := range []struct {
+		name        string
+		author      Consumer
+		repudiation *Repudiation
+	}{
+		{"nil author", nil, repudiation},
+		{"author without Id", m.UserRef(""), repudiation},
+		{"nil repudiation", author, nil},
+		{"repudiation without Id", author, &Repudiation{}},
+	} {
+		if _, err := m.NewSettlementTransfer(tt.author, tt.repudiation, amount, amount); err == nil {
+			test.Errorf("%s: expected an error", tt.name)
+		}
+	}
+	t, err := m.NewSettlementTransfer(author, repudiation, amount, Money{"EUR", 0})
+	if err != nil {
+		test.Fatal(err)
+	}
+	if t.AuthorId != "1" || t.RepudiationId != "2" {
+		test.Errorf("unexpected settlement transfer: %v", t)
+	}
+}
@@ -31,6 +31,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"net/http"
 	"net/url"
@@ -129,9 +130,30 @@ func (m *MangoPay) Option(opts ...option) {
 // request prepares and sends a well formatted HTTP request to the
 // mangopay service.
 func (s *MangoPay) request(ma mangoAction, data JsonObject) (*http.Response, error) {
+	return s.queryRequest(ma, data, nil)
+}
+
+// queryRequest is like request but also appends query parameters, i.e
+// filters or pagination, to the request's URL.
+func (s *MangoPay) queryRequest(ma mangoAction, data JsonObject, query url.Values) (*http.Response, error) {
+	method, uri, err := s.actionURI(ma, data, query)
+	if err != nil {
+		return nil, err
+	}
+	body, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.rawRequest(method, "application/json", uri, body, true)
+	return resp, err
+}
+
+// actionURI returns the HTTP method and the URI to use for an action.
+// Path variables are substituted with values from data.
+func (s *MangoPay) actionURI(ma mangoAction, data JsonObject, query url.Values) (string, string, error) {
 	mr, ok := mangoRequests[ma]
 	if !ok {
-		return nil, errors.New("Action not implemented.")
+		return "", "", errors.New("Action not implemented.")
 	}
 
 	// Create the submit url
@@ -140,25 +162,26 @@ func (s *MangoPay) request(ma mangoAction, data JsonObject) (*http.Response, err
 		// Substitute path variables, if any
 		for name := range mr.PathValues {
 			if _, ok := data[name]; !ok {
-				return nil, errors.New(fmt.Sprintf("missing keyword %s", name))
+				return "", "", errors.New(fmt.Sprintf("missing keyword %s", name))
 			}
 			path = strings.Replace(path, "{{"+name+"}}", fmt.Sprintf("%v", data[name]), -1)
 		}
-	} else {
-		path = mr.Path
 	}
-
-	body, err := json.Marshal(data)
-	if err != nil {
-		return nil, err
+	if len(query) > 0 {
+		path += "?" + query.Encode()
 	}
-	resp, err := s.rawRequest(mr.Method, "application/json",
-		fmt.Sprintf("%s%s%s", s.rootURL, s.clientId, path), body, true)
-	return resp, err
+	return mr.Method, fmt.Sprintf("%s%s%s", s.rootURL, s.clientId, path), nil
 }
 
 // rawRequest sends an HTTP request with method method to an arbitrary URI.
 func (s *MangoPay) rawRequest(method, contentType string, uri string, body []byte, useAuth bool) (*http.Response, error) {
+	return s.streamRequest(method, contentType, uri, strings.NewReader(string(body)), string(body), useAuth)
+}
+
+// streamRequest sends an HTTP request whose body is read from body, which
+// allows sending large bodies without holding them in memory. debugBody is
+// displayed in place of the body in debug mode.
+func (s *MangoPay) streamRequest(method, contentType string, uri string, body io.Reader, debugBody string, useAuth bool) (*http.Response, error) {
 	if contentType == "" {
 		return nil, errors.New("empty request's content type")
 	}
@@ -167,7 +190,7 @@ func (s *MangoPay) rawRequest(method, contentType string, uri string, body []byt
 		return nil, err
 	}
 
-	req, err := http.NewRequest(method, u.String(), strings.NewReader(string(body)))
+	req, err := http.NewRequest(method, u.String(), body)
 	if err != nil {
 		return nil, err
 	}
@@ -194,9 +217,8 @@ func (s *MangoPay) rawRequest(method, contentType string, uri string, body []byt
 				fmt.Printf("%s: %v\n", k, j)
 			}
 		}
-		rb := string(body)
-		if rb != "null" {
-			fmt.Printf("\n%s\n", rb)
+		if debugBody != "null" {
+			fmt.Printf("\n%s\n", debugBody)
 		}
 		fmt.Println("\nSending request ...")
 		fmt.Println("<<<<<<<<<<<<<<<<<<<<<< DEBUG REQUEST")
@@ -259,7 +281,12 @@ func (m *MangoPay) unMarshalJSONResponse(resp *http.Response, v interface{}) err
 
 // Generic request for any object.
 func (m *MangoPay) anyRequest(o interface{}, action mangoAction, data JsonObject) (interface{}, error) {
-	resp, err := m.request(action, data)
+	return m.anyQueryRequest(o, action, data, nil)
+}
+
+// Generic request for any object, with query parameters.
+func (m *MangoPay) anyQueryRequest(o interface{}, action mangoAction, data JsonObject, query url.Values) (interface{}, error) {
+	resp, err := m.queryRequest(action, data, query)
 	if err != nil {
 		return nil, err
 	}
@@ -312,13 +339,14 @@ func struct2string(c interface{}) string {
 	return b.String()
 }
 
+// consumerId returns the consumer's Id, or an empty string for a nil
+// consumer.
 func consumerId(c Consumer) string {
-	id := ""
-	switch c.(type) {
-	case *LegalUser:
-		id = c.(*LegalUser).Id
-	case *NaturalUser:
-		id = c.(*NaturalUser).Id
+	if c == nil {
+		return ""
+	}
+	if v := reflect.ValueOf(c); v.Kind() == reflect.Ptr && v.IsNil() {
+		return ""
 	}
-	return id
+	return c.GetId()
 }
@@ -1,6 +1,9 @@
 package mango
 
 import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"os"
 	"testing"
 )
@@ -25,3 +28,15 @@ func newTestService(test *testing.T) *MangoPay {
 	Verbosity(Debug)(service)
 	return service
 }
+
+// newFakeService returns a service sending its requests to handler, using
+// basic authentication. Request paths start with /v2/test.
+func newFakeService(test *testing.T, handler http.HandlerFunc) *MangoPay {
+	server := httptest.NewServer(handler)
+	test.Cleanup(server.Close)
+	root, err := url.Parse(server.URL + "/v2/")
+	if err != nil {
+		test.Fatal(err)
+	}
+	return &MangoPay{clientId: "test", password: "secret", rootURL: root, authMethod: BasicAuth}
+}
@@ -8,6 +8,8 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"net/url"
+	"strconv"
 )
 
 // Custom error returned in case of failed transaction.
@@ -23,6 +25,40 @@ func (e *ErrTransferFailed) Error() string {
 // List of transactions.
 type TransferList []*Transfer
 
+// TransactionFilter restricts and paginates transaction lists. Zero values
+// are ignored.
+type TransactionFilter struct {
+	Status     string // CREATED, SUCCEEDED or FAILED
+	Type       string // PAYIN, PAYOUT or TRANSFER
+	Nature     string // REGULAR, REPUDIATION, REFUND or SETTLEMENT
+	AfterDate  int64
+	BeforeDate int64
+	Page       int
+	PerPage    int
+	Sort       string // i.e CreationDate:DESC
+}
+
+// values returns the filter as query parameters.
+func (f *TransactionFilter) values() url.Values {
+	v := url.Values{}
+	if f == nil {
+		return v
+	}
+	for k, s := range map[string]string{"Status": f.Status, "Type": f.Type,
+		"Nature": f.Nature, "Sort": f.Sort} {
+		if s != "" {
+			v.Set(k, s)
+		}
+	}
+	for k, i := range map[string]int64{"AfterDate": f.AfterDate, "BeforeDate": f.BeforeDate,
+		"page": int64(f.Page), "per_page": int64(f.PerPage)} {
+		if i != 0 {
+			v.Set(k, strconv.FormatInt(i, 10))
+		}
+	}
+	return v
+}
+
 // Transfer hold details about relocating e-money from a wallet
 // to another one.
 //
@@ -141,6 +177,21 @@ func (m *MangoPay) Transfers(user Consumer) (TransferList, error) {
 	return trs, err
 }
 
+// UserTransactions finds user's transactions matching filter, which is
+// optional.
+func (m *MangoPay) UserTransactions(user Consumer, filter *TransactionFilter) (TransferList, error) {
+	id := consumerId(user)
+	if id == "" {
+		return nil, errors.New("user has empty Id")
+	}
+	trs, err := m.anyQueryRequest(new(TransferList), actionFetchUserTransfers,
+		JsonObject{"Id": id}, filter.values())
+	if err != nil {
+		return nil, err
+	}
+	return *(trs.(*TransferList)), nil
+}
+
 func (m *MangoPay) transfers(u Consumer) (TransferList, error) {
 	id := consumerId(u)
 	if id == "" {
@@ -0,0 +1,23 @@
+package mango
+
+import (
+	"testing"
+)
+
+func TestTransactionFilter_values(test *testing.T) {
+	var none *TransactionFilter
+	if v := none.values(); len(v) != 0 {
+		test.Fatalf("Expected no query parameters, got %v", v)
+	}
+	f := &TransactionFilter{
+		Status:    TransactionStatusSucceeded,
+		Nature:    TransactionNatureRegular,
+		AfterDate: 1500000000,
+		Page:      2,
+		PerPage:   50,
+	}
+	expected := "AfterDate=1500000000&Nature=REGULAR&Status=SUCCEEDED&page=2&per_page=50"
+	if q := f.values().Encode(); q != expected {
+		test.Fatalf("Invalid query: got %s, should be %s", q, expected)
+	}
+}
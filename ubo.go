@@ -0,0 +1,240 @@
+// Copyright 2015 GoTsunami. All rights reserved.
+// Use of this source code is governed by a GPL
+// license that can be found in the LICENSE file.
+
+package mango
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+)
+
+type UboDeclarationStatus string
+
+const (
+	UboDeclarationStatusCreated         UboDeclarationStatus = "CREATED"
+	UboDeclarationStatusValidationAsked UboDeclarationStatus = "VALIDATION_ASKED"
+	UboDeclarationStatusIncomplete      UboDeclarationStatus = "INCOMPLETE"
+	UboDeclarationStatusValidated       UboDeclarationStatus = "VALIDATED"
+	UboDeclarationStatusRefused         UboDeclarationStatus = "REFUSED"
+)
+
+type UboDeclarationRefusedReasonType string
+
+const (
+	UboDeclarationRefusedReasonMissingUbo               UboDeclarationRefusedReasonType = "MISSING_UBO"
+	UboDeclarationRefusedReasonWrongUboInformation      UboDeclarationRefusedReasonType = "WRONG_UBO_INFORMATION"
+	UboDeclarationRefusedReasonUboIdentityNeeded        UboDeclarationRefusedReasonType = "UBO_IDENTITY_NEEDED"
+	UboDeclarationRefusedReasonShareholdersDeclaration  UboDeclarationRefusedReasonType = "SHAREHOLDERS_DECLARATION_NEEDED"
+	UboDeclarationRefusedReasonOrganizationChartNeeded  UboDeclarationRefusedReasonType = "ORGANIZATION_CHART_NEEDED"
+	UboDeclarationRefusedReasonDocumentsNeeded          UboDeclarationRefusedReasonType = "DOCUMENTS_NEEDED"
+	UboDeclarationRefusedReasonDeclarationDoNotMatchUbo UboDeclarationRefusedReasonType = "DECLARATION_DO_NOT_MATCH_UBO_INFORMATION"
+	UboDeclarationRefusedReasonSpecificCase             UboDeclarationRefusedReasonType = "SPECIFIC_CASE"
+)
+
+// Birthplace of an ultimate beneficial owner.
+type Birthplace struct {
+	City    string
+	Country string
+}
+
+// Ubo is an ultimate beneficial owner, a natural person owning more than
+// 25% of a legal user's shares or voting rights.
+type Ubo struct {
+	ProcessIdent
+	FirstName   string
+	LastName    string
+	Address     Address
+	Nationality string
+	Birthday    int64
+	Birthplace  Birthplace
+	IsActive    bool
+}
+
+func (u *Ubo) String() string {
+	return struct2string(u)
+}
+
+// validate returns an error listing the missing fields, if any.
+func (u *Ubo) validate() error {
+	missing := []string{}
+	for _, f := range []struct{ v, name string }{
+		{u.FirstName, "FirstName"},
+		{u.LastName, "LastName"},
+		{u.Nationality, "Nationality"},
+		{u.Birthplace.City, "Birthplace.City"},
+		{u.Birthplace.Country, "Birthplace.Country"},
+	} {
+		if f.v == "" {
+			missing = append(missing, f.name)
+		}
+	}
+	if u.Birthday == 0 {
+		missing = append(missing, "Birthday")
+	}
+	errs := []string{}
+	if len(missing) > 0 {
+		errs = append(errs, "missing UBO fields: "+strings.Join(missing, ", "))
+	}
+	if err := u.Address.Validate(); err != nil {
+		errs = append(errs, err.Error())
+	}
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// List of UBO declarations.
+type UboDeclarationList []*UboDeclaration
+
+// UboDeclaration lists the ultimate beneficial owners of a BUSINESS legal
+// user. It is required to complete the KYC of such users.
+//
+// See https://docs.mangopay.com/endpoints/v2.01/ubo-declarations
+type UboDeclaration struct {
+	ProcessIdent
+	UserId        string
+	ProcessedDate int64
+	Status        UboDeclarationStatus
+	Reason        UboDeclarationRefusedReasonType
+	Message       string
+	Ubos          []*Ubo
+
+	service *MangoPay
+}
+
+func (d *UboDeclaration) String() string {
+	return struct2string(d)
+}
+
+// NewUboDeclaration creates a new, empty, UBO declaration for a legal user.
+// Add UBOs with AddUbo() then call Submit().
+func (m *MangoPay) NewUboDeclaration(user *LegalUser) (*UboDeclaration, error) {
+	if user == nil || user.Id == "" {
+		return nil, errors.New("user has empty Id")
+	}
+	any, err := m.anyRequest(new(UboDeclaration), actionCreateUboDeclaration, JsonObject{"UserId": user.Id})
+	if err != nil {
+		return nil, err
+	}
+	d := any.(*UboDeclaration)
+	d.UserId = user.Id
+	d.service = m
+	return d, nil
+}
+
+// UboDeclaration fetches a legal user's UBO declaration.
+func (m *MangoPay) UboDeclaration(user *LegalUser, id string) (*UboDeclaration, error) {
+	if user == nil || user.Id == "" {
+		return nil, errors.New("user has empty Id")
+	}
+	any, err := m.anyRequest(new(UboDeclaration), actionFetchUboDeclaration,
+		JsonObject{"UserId": user.Id, "Id": id})
+	if err != nil {
+		return nil, err
+	}
+	d := any.(*UboDeclaration)
+	d.UserId = user.Id
+	d.service = m
+	return d, nil
+}
+
+// UboDeclarations lists all legal user's UBO declarations.
+func (m *MangoPay) UboDeclarations(user *LegalUser) (UboDeclarationList, error) {
+	if user == nil || user.Id == "" {
+		return nil, errors.New("user has empty Id")
+	}
+	list, err := m.anyRequest(new(UboDeclarationList), actionFetchUserUboDeclarations,
+		JsonObject{"UserId": user.Id})
+	if err != nil {
+		return nil, err
+	}
+	casted := *(list.(*UboDeclarationList))
+	for _, d := range casted {
+		d.UserId = user.Id
+		d.service = m
+	}
+	return casted, nil
+}
+
+// AddUbo adds a beneficial owner to the declaration. On success, ubo is
+// updated with its Id and appended to d.Ubos.
+func (d *UboDeclaration) AddUbo(ubo *Ubo) error {
+	if ubo == nil {
+		return errors.New("nil UBO")
+	}
+	if err := ubo.validate(); err != nil {
+		return err
+	}
+	return d.saveUbo(ubo, actionCreateUbo)
+}
+
+// UpdateUbo updates a beneficial owner of the declaration.
+func (d *UboDeclaration) UpdateUbo(ubo *Ubo) error {
+	if ubo == nil || ubo.Id == "" {
+		return errors.New("UBO has empty Id")
+	}
+	return d.saveUbo(ubo, actionEditUbo)
+}
+
+func (d *UboDeclaration) saveUbo(ubo *Ubo, action mangoAction) error {
+	if d.Id == "" {
+		return errors.New("UBO declaration has empty Id")
+	}
+	data := JsonObject{}
+	j, err := json.Marshal(ubo)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(j, &data); err != nil {
+		return err
+	}
+
+	// Fields not allowed when creating or updating a UBO.
+	for _, field := range []string{"CreationDate", "Tag"} {
+		delete(data, field)
+	}
+	if action == actionCreateUbo {
+		delete(data, "Id")
+	}
+	data["UserId"] = d.UserId
+	data["DeclarationId"] = d.Id
+
+	ins, err := d.service.anyRequest(new(Ubo), action, data)
+	if err != nil {
+		return err
+	}
+	*ubo = *(ins.(*Ubo))
+	if action == actionCreateUbo {
+		d.Ubos = append(d.Ubos, ubo)
+	}
+	return nil
+}
+
+// Submit asks MangoPay to validate the declaration.
+func (d *UboDeclaration) Submit() error {
+	if d.Id == "" {
+		return errors.New("UBO declaration has empty Id")
+	}
+	data := JsonObject{
+		"Id":     d.Id,
+		"UserId": d.UserId,
+		"Status": UboDeclarationStatusValidationAsked,
+	}
+	ins, err := d.service.anyRequest(new(UboDeclaration), actionSubmitUboDeclaration, data)
+	if err != nil {
+		return err
+	}
+	serv, userId := d.service, d.UserId
+	*d = *(ins.(*UboDeclaration))
+	d.service, d.UserId = serv, userId
+	return nil
+}
+
+// Refused returns true if the declaration has been refused or is
+// incomplete. See Reason and Message for details.
+func (d *UboDeclaration) Refused() bool {
+	return d.Status == UboDeclarationStatusRefused || d.Status == UboDeclarationStatusIncomplete
+}
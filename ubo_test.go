@@ -0,0 +1,37 @@
+package mango
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestUboDeclaration_AddUboMissingFields(test *testing.T) {
+	d := &UboDeclaration{ProcessIdent: ProcessIdent{Id: "d1"}, UserId: "u1"}
+	err := d.AddUbo(&Ubo{FirstName: "Alice", Address: Address{City: "Paris"}})
+	if err == nil {
+		test.Fatal("Expected error for incomplete UBO")
+	}
+	for _, field := range []string{"LastName", "Nationality", "Birthday", "Birthplace.City",
+		"invalid address: missing AddressLine1, Country"} {
+		if !strings.Contains(err.Error(), field) {
+			test.Errorf("Missing field %s not reported in %q", field, err)
+		}
+	}
+	if strings.Contains(err.Error(), "FirstName") {
+		test.Errorf("Filled fields reported as missing in %q", err)
+	}
+}
+
+func TestUbo_ValidateAddress(test *testing.T) {
+	// Addresses in Ireland have no postal code.
+	u := &Ubo{FirstName: "Alice", LastName: "Murphy", Nationality: "IE", Birthday: 1,
+		Address:    Address{AddressLine1: "1 Main Street", City: "Dublin", Country: "IE"},
+		Birthplace: Birthplace{City: "Cork", Country: "IE"}}
+	if err := u.validate(); err != nil {
+		test.Errorf("valid UBO rejected: %v", err)
+	}
+	u.Address.Country = "FR"
+	if err := u.validate(); err == nil || !strings.Contains(err.Error(), "PostalCode") {
+		test.Errorf("expected a missing postal code, got %v", err)
+	}
+}
@@ -4,58 +4,236 @@
 
 package mango
 
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
 const (
 	KYCLevelLight   = "LIGHT"
 	KYCLevelRegular = "REGULAR"
 )
 
+// Legal capacity of a natural user.
+const (
+	CapacityNormal      = "NORMAL"
+	CapacityDeclarative = "DECLARATIVE"
+)
+
 const (
 	PersonTypeNatural = "NATURAL"
 	PersonTypeLegal   = "LEGAL"
 )
 
+// UserCategory defines what a user can do on the platform, and thus which
+// user fields are required by MangoPay.
+type UserCategory string
+
+const (
+	// A PAYER can only pay in and transfer money to other users.
+	UserCategoryPayer UserCategory = "PAYER"
+	// An OWNER can also receive money and pay it out to a bank account.
+	UserCategoryOwner UserCategory = "OWNER"
+)
+
+// ErrUserValidation is returned when a user lacks fields required by its
+// category. All missing fields are reported at once.
+type ErrUserValidation struct {
+	Category UserCategory
+	Missing  []string
+}
+
+func (e *ErrUserValidation) Error() string {
+	if e.Category == "" {
+		return fmt.Sprintf("invalid user: missing %s", strings.Join(e.Missing, ", "))
+	}
+	return fmt.Sprintf("invalid %s user: missing %s", e.Category, strings.Join(e.Missing, ", "))
+}
+
+// userFields collects the names of empty required fields.
+type userFields []string
+
+func (f *userFields) require(name string, ok bool) {
+	if !ok {
+		*f = append(*f, name)
+	}
+}
+
+// err returns an ErrUserValidation if some fields are missing.
+func (f userFields) err(category UserCategory) error {
+	if len(f) == 0 {
+		return nil
+	}
+	return &ErrUserValidation{Category: category, Missing: f}
+}
+
 // A Consumer is a legal or natural user with zero, one or
 // more wallets and tranfers.
 type Consumer interface {
+	// User's Id
+	GetId() string
 	// All user's wallets
 	Wallets() (WalletList, error)
 	// All user's transactions
 	Transfers() (TransferList, error)
 }
 
-type UserList []*User
+// UserList holds natural and legal users.
+type UserList []Person
+
+// Person is implemented by both natural and legal users. It gives access to
+// the fields common to all users without knowing their exact type, which
+// can then be found out with a type switch on *NaturalUser or *LegalUser.
+type Person interface {
+	Consumer
+	GetId() string
+	GetPersonType() string
+	GetKYCLevel() string
+	GetEmail() string
+}
 
 // User is used by the user activity API and describe common fields to
 // both natural and legal users.
 type User struct {
 	ProcessIdent
-	PersonType string
-	Email      string
+	PersonType                     string
+	KYCLevel                       string
+	UserStatus                     string
+	TermsAndConditionsAcceptedDate int64
+	Email                          string
 }
 
 func (u *User) String() string {
 	return struct2string(u)
 }
 
+// GetId returns the user's Id.
+func (u *User) GetId() string { return u.Id }
+
+// GetPersonType returns either PersonTypeNatural or PersonTypeLegal.
+func (u *User) GetPersonType() string { return u.PersonType }
+
+// GetKYCLevel returns either KYCLevelLight or KYCLevelRegular.
+func (u *User) GetKYCLevel() string { return u.KYCLevel }
+
+// GetEmail returns the user's email.
+func (u *User) GetEmail() string { return u.Email }
+
+// UserRef references a user by its Id only. It can be used as a Consumer
+// wherever the full user object is not needed, i.e when creating transfers
+// or payins, avoiding an extra request to fetch the user.
+type UserRef struct {
+	User
+	service *MangoPay
+}
+
+// UserRef returns a reference to the user with this Id. No request is
+// sent to MangoPay: the Id is not checked for existence.
+func (m *MangoPay) UserRef(id string) *UserRef {
+	u := &UserRef{service: m}
+	u.Id = id
+	return u
+}
+
+// Wallets returns user's wallets.
+func (u *UserRef) Wallets() (WalletList, error) {
+	return u.service.wallets(u)
+}
+
+// Transfers gets all user's transactions.
+func (u *UserRef) Transfers() (TransferList, error) {
+	return u.service.transfers(u)
+}
+
+// ScopeBlocked tells which money flows are blocked for a user.
+type ScopeBlocked struct {
+	Inflows  bool
+	Outflows bool
+}
+
+// UserRegulatory holds the regulatory status of a user. A blocked user
+// can't receive (Inflows) or send (Outflows) money until the reason given
+// by ActionCode is resolved.
+type UserRegulatory struct {
+	UserId       string
+	ActionCode   string
+	ScopeBlocked ScopeBlocked
+}
+
+func (r *UserRegulatory) String() string {
+	return struct2string(r)
+}
+
+// Blocked returns true if any money flow is blocked for the user.
+func (r *UserRegulatory) Blocked() bool {
+	return r.ScopeBlocked.Inflows || r.ScopeBlocked.Outflows
+}
+
+// UserRegulatory fetches the regulatory status of a user, which tells
+// whether payins or payouts are currently blocked.
+func (m *MangoPay) UserRegulatory(userId string) (*UserRegulatory, error) {
+	if userId == "" {
+		return nil, errors.New("user has empty Id")
+	}
+	r, err := m.anyRequest(new(UserRegulatory), actionFetchUserRegulatory, JsonObject{"Id": userId})
+	if err != nil {
+		return nil, err
+	}
+	return r.(*UserRegulatory), nil
+}
+
 // Users returns a list of all registered users, either natural
 // or legal.
 func (m *MangoPay) Users() (UserList, error) {
-	resp, err := m.request(actionAllUsers, nil)
+	list, err := m.anyRequest(new([]json.RawMessage), actionAllUsers, nil)
 	if err != nil {
 		return nil, err
 	}
-	ul := UserList{}
-	if err := m.unMarshalJSONResponse(resp, &ul); err != nil {
-		return nil, err
+	raws := *(list.(*[]json.RawMessage))
+	ul := make(UserList, 0, len(raws))
+	for _, raw := range raws {
+		u, err := m.decodeUser(raw)
+		if err != nil {
+			return nil, err
+		}
+		ul = append(ul, u)
 	}
 	return ul, nil
 }
 
-// User fetch a user (natural or legal) using the Id attribute.
-func (m *MangoPay) User(id string) (*User, error) {
-	u, err := m.anyRequest(new(User), actionFetchUser, JsonObject{"Id": id})
+// User fetch a user (natural or legal) using the Id attribute. The
+// returned value is either a *NaturalUser or a *LegalUser.
+func (m *MangoPay) User(id string) (Person, error) {
+	raw, err := m.anyRequest(new(json.RawMessage), actionFetchUser, JsonObject{"Id": id})
 	if err != nil {
 		return nil, err
 	}
-	return u.(*User), nil
+	return m.decodeUser(*(raw.(*json.RawMessage)))
+}
+
+// decodeUser decodes a natural or legal user depending on its PersonType.
+func (m *MangoPay) decodeUser(raw json.RawMessage) (Person, error) {
+	var u User
+	if err := json.Unmarshal(raw, &u); err != nil {
+		return nil, err
+	}
+	switch u.PersonType {
+	case PersonTypeNatural:
+		nu := new(NaturalUser)
+		if err := json.Unmarshal(raw, nu); err != nil {
+			return nil, err
+		}
+		nu.service = m
+		return nu, nil
+	case PersonTypeLegal:
+		lu := new(LegalUser)
+		if err := json.Unmarshal(raw, lu); err != nil {
+			return nil, err
+		}
+		lu.service = m
+		return lu, nil
+	}
+	return nil, fmt.Errorf("user %s: unknown person type %q", u.Id, u.PersonType)
 }
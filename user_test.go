@@ -1,6 +1,8 @@
 package mango
 
 import (
+	"encoding/json"
+	"net/http"
 	"testing"
 	"time"
 )
@@ -19,8 +21,124 @@ func TestNaturalUserSave(test *testing.T) {
 }
 
 func createTestUser(serv *MangoPay) *NaturalUser {
-	user := serv.NewNaturalUser("Sergey", "Yarmonov", "sergey.yarmonov@gmail.com", "cat",
+	user := serv.NewNaturalUser("Sergey", "Yarmonov", "sergey.yarmonov@gmail.com", UserCategoryPayer,
 		time.Date(1988, time.January, 18, 0, 0, 0, 0, time.UTC).Unix(), "DE", "DE", true)
 	user.IncomeRange = 3
 	return user
 }
+
+func TestUserValidate(test *testing.T) {
+	u := &NaturalUser{FirstName: "Sergey", LastName: "Yarmonov",
+		UserCategory: UserCategoryPayer, TermsAndConditionsAccepted: true}
+	u.Email = "s@y.org"
+	if err := u.Validate(); err != nil {
+		test.Fatal("valid payer rejected:", err)
+	}
+	u.UserCategory = UserCategoryOwner
+	err := u.Validate()
+	verr, ok := err.(*ErrUserValidation)
+	if !ok {
+		test.Fatalf("expected *ErrUserValidation, got %v", err)
+	}
+	if len(verr.Missing) != 4 {
+		test.Errorf("expected 4 missing fields, got %v", verr.Missing)
+	}
+	u.Address = &Address{}
+	if err := u.Validate(); err == nil || len(err.(*ErrUserValidation).Missing) != 4 {
+		test.Errorf("expected an empty address to be missing, got %v", err)
+	}
+
+	l := &LegalUser{Name: "Acme", LegalPersonType: LegalPersonTypeBusiness,
+		UserCategory: UserCategoryOwner}
+	l.Email = "a@acme.org"
+	err = l.Validate()
+	verr, ok = err.(*ErrUserValidation)
+	if !ok {
+		test.Fatalf("expected *ErrUserValidation, got %v", err)
+	}
+	found := false
+	for _, f := range verr.Missing {
+		if f == "CompanyNumber" {
+			found = true
+		}
+	}
+	if !found {
+		test.Errorf("expected CompanyNumber to be required, got %v", verr.Missing)
+	}
+}
+
+func TestDecodeUser(test *testing.T) {
+	m := new(MangoPay)
+	for _, tt := range []struct {
+		raw  string
+		kind string
+	}{
+		{`{"Id": "1", "PersonType": "NATURAL", "FirstName": "Sergey", "KYCLevel": "LIGHT"}`, "natural"},
+		{`{"Id": "2", "PersonType": "LEGAL", "Name": "Acme", "KYCLevel": "REGULAR"}`, "legal"},
+	} {
+		u, err := m.decodeUser([]byte(tt.raw))
+		if err != nil {
+			test.Fatal(err)
+		}
+		switch v := u.(type) {
+		case *NaturalUser:
+			if tt.kind != "natural" || v.FirstName != "Sergey" || v.service != m {
+				test.Errorf("bad natural user decoding: %v", v)
+			}
+		case *LegalUser:
+			if tt.kind != "legal" || v.Name != "Acme" || v.service != m {
+				test.Errorf("bad legal user decoding: %v", v)
+			}
+		}
+		if u.GetKYCLevel() == "" {
+			test.Errorf("expected a KYC level for user %s", u.GetId())
+		}
+	}
+	if _, err := m.decodeUser([]byte(`{"Id": "3", "PersonType": "ALIEN"}`)); err == nil {
+		test.Error("expected an error for an unknown person type")
+	}
+}
+
+func TestUserRef(test *testing.T) {
+	m := new(MangoPay)
+	var c Consumer = m.UserRef("42")
+	if id := consumerId(c); id != "42" {
+		test.Errorf("expected Id 42, got %q", id)
+	}
+	var nu *NaturalUser
+	if id := consumerId(nu); id != "" {
+		test.Errorf("expected empty Id for nil user, got %q", id)
+	}
+	if _, err := m.NewTransfer(c, Money{"EUR", 100}, Money{"EUR", 0},
+		m.WalletRef("1"), m.WalletRef("2")); err != nil {
+		test.Error(err)
+	}
+}
+
+func TestEditUserAddress(test *testing.T) {
+	var body JsonObject
+	m := newFakeService(test, func(w http.ResponseWriter, req *http.Request) {
+		body = nil
+		if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+			test.Error(err)
+		}
+		w.Write([]byte(`{"Id": "1", "PersonType": "NATURAL"}`))
+	})
+	for _, addr := range []string{
+		`{"AddressLine1": null, "AddressLine2": null, "City": null, "Region": null, "PostalCode": null, "Country": null}`,
+		`"1 rue de la Paix"`,
+	} {
+		u := &NaturalUser{service: m}
+		if err := json.Unmarshal([]byte(`{"Id": "1", "FirstName": "Sergey", "Address": `+addr+`}`), u); err != nil {
+			test.Fatal(err)
+		}
+		u.FirstName = "Serguei"
+		empty := u.Address.IsEmpty()
+		if err := u.Save(); err != nil {
+			test.Errorf("unable to edit user with address %s: %v", addr, err)
+		}
+		if _, ok := body["Address"]; ok == empty {
+			test.Errorf("unexpected address sent for %s: %v", addr, body["Address"])
+		}
+	}
+}
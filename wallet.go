@@ -13,7 +13,10 @@ import (
 const (
 	FundsTypeDefault = "DEFAULT"
 	FundsTypeFees    = "FEES"
-	FundsTypeCreadit = "CREDIT"
+	FundsTypeCredit  = "CREDIT"
+
+	// Deprecated: use FundsTypeCredit.
+	FundsTypeCreadit = FundsTypeCredit
 )
 
 // List of wallets.
@@ -41,6 +44,7 @@ type Wallet struct {
 	Description string
 	Currency    string
 	Balance     Money
+	FundsType   string `json:",omitempty"` // DEFAULT, FEES or CREDIT
 	service     *MangoPay
 }
 
@@ -68,6 +72,15 @@ func (m *MangoPay) NewWallet(owners ConsumerList, desc string, currency string)
 	return w, nil
 }
 
+// WalletRef returns a reference to the wallet with this Id. No request
+// is sent to MangoPay: only the Id is set, which is enough to use the
+// wallet in transfers, payins or payouts.
+func (m *MangoPay) WalletRef(id string) *Wallet {
+	w := &Wallet{service: m}
+	w.Id = id
+	return w
+}
+
 // Save creates or updates a legal user. The Create API is used
 // if the user's Id is an empty string. The Edit API is used when
 // the Id is a non-empty string.
@@ -99,6 +112,7 @@ func (w *Wallet) Save() error {
 	}
 	delete(data, "CreationDate")
 	delete(data, "Balance")
+	delete(data, "FundsType")
 
 	if action == actionEditWallet {
 		// Delete empty values so that existing ones don't get
@@ -163,3 +177,61 @@ func (m *MangoPay) wallets(u Consumer) (WalletList, error) {
 func (m *MangoPay) Wallets(user Consumer) (WalletList, error) {
 	return m.wallets(user)
 }
+
+// ClientWallets returns all the platform's wallets: the FEES wallets
+// collecting fees and the CREDIT wallets used for repudiations, one per
+// currency.
+func (m *MangoPay) ClientWallets() (WalletList, error) {
+	return m.clientWallets(actionFetchClientWallets, nil)
+}
+
+// ClientWalletsByFundsType returns the platform's wallets of the given
+// funds type (FundsTypeFees or FundsTypeCredit).
+func (m *MangoPay) ClientWalletsByFundsType(fundsType string) (WalletList, error) {
+	if fundsType == "" {
+		return nil, errors.New("empty funds type")
+	}
+	return m.clientWallets(actionFetchClientWalletsByFundsType, JsonObject{"FundsType": fundsType})
+}
+
+func (m *MangoPay) clientWallets(action mangoAction, data JsonObject) (WalletList, error) {
+	list, err := m.anyRequest(new(WalletList), action, data)
+	if err != nil {
+		return nil, err
+	}
+	casted := *(list.(*WalletList))
+	for _, w := range casted {
+		w.service = m
+	}
+	return casted, nil
+}
+
+// ClientWallet returns the platform's wallet of the given funds type and
+// currency.
+func (m *MangoPay) ClientWallet(fundsType, currency string) (*Wallet, error) {
+	if fundsType == "" || currency == "" {
+		return nil, errors.New("empty funds type or currency")
+	}
+	w, err := m.anyRequest(new(Wallet), actionFetchClientWallet,
+		JsonObject{"FundsType": fundsType, "Currency": currency})
+	if err != nil {
+		return nil, err
+	}
+	wallet := w.(*Wallet)
+	wallet.service = m
+	return wallet, nil
+}
+
+// ClientWalletTransactions returns the transactions of the platform's wallet
+// of the given funds type and currency. filter is optional.
+func (m *MangoPay) ClientWalletTransactions(fundsType, currency string, filter *TransactionFilter) (TransferList, error) {
+	if fundsType == "" || currency == "" {
+		return nil, errors.New("empty funds type or currency")
+	}
+	k, err := m.anyQueryRequest(new(TransferList), actionFetchClientWalletTransactions,
+		JsonObject{"FundsType": fundsType, "Currency": currency}, filter.values())
+	if err != nil {
+		return nil, err
+	}
+	return *(k.(*TransferList)), nil
+}